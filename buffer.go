@@ -25,6 +25,7 @@ const (
 	coma         byte = ','
 	colon        byte = ':'
 	backslash    byte = '\\'
+	slash        byte = '/'
 	skipS        byte = ' '
 	skipN        byte = '\n'
 	skipR        byte = '\r'
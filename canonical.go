@@ -0,0 +1,141 @@
+package ajson
+
+import (
+	"math"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// MarshalCanonical returns the canonical form of the node, in accordance to RFC 8785 (JSON Canonicalization Scheme):
+// without whitespaces, with keys sorted by UTF-16 code units, numbers formatted as in ECMAScript and minimal
+// escaping of strings. Whole tree is re-encoded, whether nodes are untouched or not.
+//
+// It returns an error for NaN and infinite numbers, and for strings with invalid UTF-8.
+func MarshalCanonical(node *Node) ([]byte, error) {
+	return canonical(make([]byte, 0), node)
+}
+
+// canonical appends canonical form of the node to the result
+func canonical(result []byte, node *Node) ([]byte, error) {
+	if node == nil {
+		return nil, errorUnparsed()
+	}
+	switch node.Type() {
+	case Null:
+		return append(result, _null...), nil
+	case Bool:
+		value, err := node.GetBool()
+		if err != nil {
+			return nil, err
+		}
+		if value {
+			return append(result, _true...), nil
+		}
+		return append(result, _false...), nil
+	case Numeric:
+		value, err := node.GetNumeric()
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errorRequest("unsupported number %v", value)
+		}
+		if value == 0 {
+			// negative zero is "0" as well
+			return append(result, '0'), nil
+		}
+		return append(result, formatFloat(value)...), nil
+	case String:
+		value, err := node.GetString()
+		if err != nil {
+			return nil, err
+		}
+		return canonicalString(result, value)
+	case Array:
+		var err error
+		result = append(result, bracketL)
+		for i, child := range node.Inheritors() {
+			if i != 0 {
+				result = append(result, coma)
+			}
+			if result, err = canonical(result, child); err != nil {
+				return nil, err
+			}
+		}
+		return append(result, bracketR), nil
+	case Object:
+		var err error
+		keys := canonicalKeys(node.Keys())
+		result = append(result, bracesL)
+		for i, key := range keys {
+			if i != 0 {
+				result = append(result, coma)
+			}
+			if result, err = canonicalString(result, key); err != nil {
+				return nil, err
+			}
+			result = append(result, colon)
+			if result, err = canonical(result, node.children[key]); err != nil {
+				return nil, err
+			}
+		}
+		return append(result, bracesR), nil
+	}
+	return nil, errorType()
+}
+
+// canonicalKeys sorts keys by their UTF-16 code units
+func canonicalKeys(keys []string) []string {
+	units := make(map[string][]uint16, len(keys))
+	for _, key := range keys {
+		units[key] = utf16.Encode([]rune(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := units[keys[i]], units[keys[j]]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return keys
+}
+
+// canonicalString appends quoted string to the result, with escaping of quotes, backslash and control characters
+// only
+func canonicalString(result []byte, value string) ([]byte, error) {
+	if !utf8.ValidString(value) {
+		return nil, errorRequest("invalid UTF-8 string '%s'", value)
+	}
+	result = append(result, quotes)
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= ' ' && c != quotes && c != backslash {
+			continue
+		}
+		result = append(result, value[start:i]...)
+		result = append(result, backslash)
+		switch c {
+		case quotes, backslash:
+			result = append(result, c)
+		case '\b':
+			result = append(result, 'b')
+		case '\t':
+			result = append(result, 't')
+		case '\n':
+			result = append(result, 'n')
+		case '\f':
+			result = append(result, 'f')
+		case '\r':
+			result = append(result, 'r')
+		default:
+			result = append(result, 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		}
+		start = i + 1
+	}
+	result = append(result, value[start:]...)
+	return append(result, quotes), nil
+}
@@ -0,0 +1,89 @@
+package ajson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleMarshalCanonical() {
+	root := Must(Unmarshal([]byte(`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`)))
+	result, err := MarshalCanonical(root)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(result))
+	// Output:
+	// {"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "sorting", input: `{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`,
+			expected: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"},
+		{name: "nested", input: ` { "b" : [ { "d" : 1 , "c" : 2 } ] , "a" : { } } `, expected: `{"a":{},"b":[{"c":2,"d":1}]}`},
+		{name: "prefix keys", input: `{"ab":1,"a":2,"":3}`, expected: `{"":3,"a":2,"ab":1}`},
+		{name: "zero", input: `[0, -0, 0.0, -0e10]`, expected: `[0,0,0,0]`},
+		{name: "integers", input: `[1, -1, 1e2, 100000000000000000000, 1e21, 9007199254740993]`, expected: `[1,-1,100,100000000000000000000,1e+21,9007199254740992]`},
+		{name: "fractions", input: `[0.1, 1e-6, 1e-7, 1.5e-10, 123.456e5, 5e-324, 1.7976931348623157e308]`, expected: `[0.1,0.000001,1e-7,1.5e-10,12345600,5e-324,1.7976931348623157e+308]`},
+		{name: "escapes", input: `"\u0000\u0007\b\f\n\r\t\u001f <>&/\u2028\u007f"`, expected: "\"\\u0000\\u0007\\b\\f\\n\\r\\t\\u001f <>&/\u2028\u007f\""},
+		{name: "scalar", input: ` true `, expected: `true`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := MarshalCanonical(Must(Unmarshal([]byte(test.input))))
+			if err != nil {
+				t.Fatalf("MarshalCanonical() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("MarshalCanonical() wrong result:\n%s\n%s", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarshalCanonical_dirty(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"b": 1.50, "a": "x"}`)))
+	if err := root.AppendObject("c", ArrayNode("", []*Node{NumericNode("", 1e-7), StringNode("", "é")})); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.MustKey("a").SetNumeric(-0.5); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	lazy := Must(UnmarshalWithOptions([]byte(root.String()), Options{Lazy: true}))
+	for _, node := range []*Node{root, lazy} {
+		if result := string(mustBytes(MarshalCanonical(node))); result != `{"a":-0.5,"b":1.5,"c":[1e-7,"é"]}` {
+			t.Errorf("MarshalCanonical() wrong result: %s", result)
+		}
+	}
+}
+
+func TestMarshalCanonical_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		node *Node
+	}{
+		{name: "nil", node: nil},
+		{name: "NaN", node: NumericNode("", math.NaN())},
+		{name: "Inf", node: ArrayNode("", []*Node{NumericNode("", math.Inf(-1))})},
+		{name: "JSON5 Infinity", node: Must(UnmarshalJSON5([]byte(`{a: Infinity}`)))},
+		{name: "invalid UTF-8", node: StringNode("", "\xff")},
+		{name: "invalid UTF-8 key", node: ObjectNode("", map[string]*Node{"\xff": NullNode("")})},
+		{name: "unparsed", node: &Node{_type: Numeric}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result, err := MarshalCanonical(test.node); err == nil {
+				t.Errorf("MarshalCanonical() expected error, got: %s", result)
+			}
+		})
+	}
+}
@@ -91,7 +91,12 @@ func apply(cfg config, data []byte, line int) {
 
 	root, err := ajson.Unmarshal(data)
 	if err != nil {
-		mlFatal(cfg, "%s parsing JSON: %s", msg, err)
+		var jerr ajson.Error
+		if errors.As(err, &jerr) && jerr.Snippet != "" {
+			mlFatal(cfg, "%s parsing JSON: %s\n%s", msg, err, jerr.Snippet)
+		} else {
+			mlFatal(cfg, "%s parsing JSON: %s", msg, err)
+		}
 		return
 	}
 
@@ -9,7 +9,7 @@ import (
 const (
 	cl States = -2 /* colon           */
 	cm States = -3 /* comma           */
-	//qt States = -4 /* quote           */
+	qt States = -4 /* quote           */
 	bo States = -5 /* bracket open    */
 	co States = -6 /* curly br. open  */
 	bc States = -7 /* bracket close   */
@@ -17,20 +17,78 @@ const (
 	ec States = -9 /* curly br. empty */
 )
 
+// Options is a set of restrictions for the UnmarshalWithOptions.
+type Options struct {
+	// MaxDepth is the maximum nesting level of the containers, zero means no limit (10000 for JSON5).
+	MaxDepth int
+	// MaxBytes is the maximum size of the data, zero means no limit.
+	MaxBytes int
+	// DuplicateKeys is the policy for the repeated keys of an object.
+	DuplicateKeys DuplicateKeyPolicy
+	// JSON5 allows the lenient JSON5 syntax, see UnmarshalJSON5.
+	JSON5 bool
+	// Lazy creates children of the containers only on the first access to them (GetKey, GetIndex, Inheritors,
+	// JSONPath, etc.), the whole data is validated at once. It can't be used with JSON5 or DuplicateKeyError.
+	Lazy bool
+}
+
+// DuplicateKeyPolicy defines the way to process the repeated keys of an object.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLastWins means that the last value of the key will be stored (default)
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeyFirstWins means that the first value of the key will be stored
+	DuplicateKeyFirstWins
+	// DuplicateKeyError means that DuplicateKey error will be returned
+	DuplicateKeyError
+)
+
 // Unmarshal parses the JSON-encoded data and return the root node of struct.
 //
 // Doesn't calculate values, just type of stored value. It will store link to the data, on all life long.
 func Unmarshal(data []byte) (root *Node, err error) {
+	return UnmarshalWithOptions(data, Options{})
+}
+
+// UnmarshalWithOptions do the same thing as Unmarshal, but validates the data with the given options.
+func UnmarshalWithOptions(data []byte, options Options) (root *Node, err error) {
+	if options.MaxBytes > 0 && len(data) > options.MaxBytes {
+		return nil, errorLimit(options.MaxBytes, "max bytes %d", options.MaxBytes)
+	}
+	if options.Lazy {
+		return unmarshalLazy(data, options)
+	}
+	if options.JSON5 {
+		return unmarshalJSON5(data, options)
+	}
 	buf := newBuffer(data)
 	var (
-		state   States
-		key     *string
-		current *Node
-		useKey  = func() **string {
+		state    States
+		key      *string
+		current  *Node
+		shadowed *Node
+		depth    int
+		useKey   = func() **string {
 			tmp := cptrs(key)
 			key = nil
 			return &tmp
 		}
+		create = func(_type NodeType) (node *Node, err error) {
+			if _type == Object || _type == Array {
+				depth++
+				if options.MaxDepth > 0 && depth > options.MaxDepth {
+					return nil, errorLimit(buf.index, "max depth %d", options.MaxDepth)
+				}
+			}
+			parent := current
+			node, err = newNode(current, buf, _type, useKey())
+			if err == nil && shadowed != nil {
+				parent.children[*node.key] = shadowed
+				shadowed = nil
+			}
+			return
+		}
 	)
 
 	_, err = buf.first()
@@ -50,11 +108,20 @@ func Unmarshal(data []byte) (root *Node, err error) {
 			case ST:
 				if current != nil && current.IsObject() && key == nil {
 					// Detected: Key
+					start := buf.index
 					key, err = getString(buf)
+					if err == nil && current.children[*key] != nil {
+						switch options.DuplicateKeys {
+						case DuplicateKeyFirstWins:
+							shadowed = current.children[*key]
+						case DuplicateKeyError:
+							err = errorDuplicate(start, *key)
+						}
+					}
 					buf.state = CO
 				} else {
 					// Detected: String
-					current, err = newNode(current, buf, String, useKey())
+					current, err = create(String)
 					if err != nil {
 						break
 					}
@@ -66,7 +133,7 @@ func Unmarshal(data []byte) (root *Node, err error) {
 					}
 				}
 			case MI, ZE, IN:
-				current, err = newNode(current, buf, Numeric, useKey())
+				current, err = create(Numeric)
 				if err != nil {
 					break
 				}
@@ -78,7 +145,7 @@ func Unmarshal(data []byte) (root *Node, err error) {
 					current = current.parent
 				}
 			case T1, F1:
-				current, err = newNode(current, buf, Bool, useKey())
+				current, err = create(Bool)
 				if err != nil {
 					break
 				}
@@ -93,7 +160,7 @@ func Unmarshal(data []byte) (root *Node, err error) {
 					current = current.parent
 				}
 			case N1:
-				current, err = newNode(current, buf, Null, useKey())
+				current, err = create(Null)
 				if err != nil {
 					break
 				}
@@ -114,6 +181,7 @@ func Unmarshal(data []byte) (root *Node, err error) {
 				}
 				fallthrough
 			case cc: /* } */
+				depth--
 				if current != nil && current.IsObject() && !current.ready() {
 					current.borders[1] = buf.index + 1
 					if current.parent != nil {
@@ -124,6 +192,7 @@ func Unmarshal(data []byte) (root *Node, err error) {
 				}
 				buf.state = OK
 			case bc: /* ] */
+				depth--
 				if current != nil && current.IsArray() && !current.ready() {
 					current.borders[1] = buf.index + 1
 					if current.parent != nil {
@@ -134,10 +203,10 @@ func Unmarshal(data []byte) (root *Node, err error) {
 				}
 				buf.state = OK
 			case co: /* { */
-				current, err = newNode(current, buf, Object, useKey())
+				current, err = create(Object)
 				buf.state = OB
 			case bo: /* [ */
-				current, err = newNode(current, buf, Array, useKey())
+				current, err = create(Array)
 				buf.state = AR
 			case cm: /* , */
 				if current == nil {
@@ -795,7 +795,7 @@ func ExampleMust_panic() {
 	root := Must(Unmarshal(data))
 	fmt.Printf("Object has %d inheritors inside", root.Size())
 	// Output:
-	// Unmarshal(): wrong symbol ']' at 1
+	// Unmarshal(): wrong symbol ']' at 1 (line 1, column 2)
 }
 
 func TestUnmarshal_main(t *testing.T) {
@@ -2893,3 +2893,104 @@ line"]`)},
 		})
 	}
 }
+
+func TestUnmarshalWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options Options
+		want    interface{}
+		err     *Error
+	}{
+		{
+			name:    "default",
+			input:   `{"a":1,"b":{"c":[2]},"a":3}`,
+			options: Options{},
+			want:    map[string]interface{}{"a": 3.0, "b": map[string]interface{}{"c": []interface{}{2.0}}},
+		},
+		{
+			name:    "max depth: valid",
+			input:   `{"a":[{"b":[]}]}`,
+			options: Options{MaxDepth: 4},
+			want:    map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": []interface{}{}}}},
+		},
+		{
+			name:    "max depth: siblings",
+			input:   `[[],[],{},[[]]]`,
+			options: Options{MaxDepth: 3},
+			want:    []interface{}{[]interface{}{}, []interface{}{}, map[string]interface{}{}, []interface{}{[]interface{}{}}},
+		},
+		{
+			name:    "max depth: exceeded",
+			input:   `{"a":[{"b":[]}]}`,
+			options: Options{MaxDepth: 3},
+			err:     &Error{Type: LimitExceeded, Index: 11, Message: "max depth 3"},
+		},
+		{
+			name:    "max depth: scalar",
+			input:   `"value"`,
+			options: Options{MaxDepth: 1},
+			want:    "value",
+		},
+		{
+			name:    "max bytes: valid",
+			input:   `[1,2,3]`,
+			options: Options{MaxBytes: 7},
+			want:    []interface{}{1.0, 2.0, 3.0},
+		},
+		{
+			name:    "max bytes: exceeded",
+			input:   `[1,2,3] `,
+			options: Options{MaxBytes: 7},
+			err:     &Error{Type: LimitExceeded, Index: 7, Message: "max bytes 7"},
+		},
+		{
+			name:    "duplicate: last wins",
+			input:   `{"a":1,"a":{"b":2}}`,
+			options: Options{DuplicateKeys: DuplicateKeyLastWins},
+			want:    map[string]interface{}{"a": map[string]interface{}{"b": 2.0}},
+		},
+		{
+			name:    "duplicate: first wins",
+			input:   `{"a":1,"a":{"b":2},"c":[{"d":1,"d":2}]}`,
+			options: Options{DuplicateKeys: DuplicateKeyFirstWins},
+			want:    map[string]interface{}{"a": 1.0, "c": []interface{}{map[string]interface{}{"d": 1.0}}},
+		},
+		{
+			name:    "duplicate: error",
+			input:   `{"a":1, "b":{"a":1}, "a":2}`,
+			options: Options{DuplicateKeys: DuplicateKeyError},
+			err:     &Error{Type: DuplicateKey, Index: 21, Message: "a"},
+		},
+		{
+			name:    "duplicate: escaped",
+			input:   `{"a":1, "\u0061":2}`,
+			options: Options{DuplicateKeys: DuplicateKeyError},
+			err:     &Error{Type: DuplicateKey, Index: 8, Message: "a"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := UnmarshalWithOptions([]byte(test.input), test.options)
+			if test.err != nil {
+				if err == nil {
+					t.Fatalf("UnmarshalWithOptions() expected error, got: %s", root)
+				}
+				if !reflect.DeepEqual(err, *test.err) {
+					t.Errorf("UnmarshalWithOptions() wrong error: %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+			}
+			got, err := root.Unpack()
+			if err != nil {
+				t.Fatalf("Unpack() unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("UnmarshalWithOptions() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package ajson
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+const decoderMinRead = 512
+
+// Decoder reads and decodes JSON values from an input stream.
+//
+// Stream can contain any amount of top-level JSON values, separated by whitespaces (e.g. NDJSON).
+// Each value can take several lines.
+type Decoder struct {
+	reader  io.Reader
+	data    []byte
+	index   int
+	offset  int
+	line    int
+	column  int
+	scanner scanner
+	eof     bool
+	err     error
+}
+
+// NewDecoder returns a new decoder that reads from reader.
+func NewDecoder(reader io.Reader) *Decoder {
+	return &Decoder{
+		reader: reader,
+	}
+}
+
+// Decode reads the next JSON value from the input and returns its root node.
+//
+// It returns io.EOF when the input has no more values. Each root node has its own copy of the source data.
+func (d *Decoder) Decode() (root *Node, err error) {
+	if !d.More() {
+		return nil, d.err
+	}
+
+	d.scanner.reset()
+	i := d.index
+scan:
+	for {
+		for ; i < len(d.data); i++ {
+			if !d.scanner.feed(d.data[i]) {
+				d.err = d.error(WrongSymbol, i)
+				return nil, d.err
+			}
+			if d.scanner.done() {
+				i++
+				break scan
+			}
+		}
+		dropped, err := d.refill()
+		i -= dropped
+		if err == io.EOF && d.scanner.complete() {
+			break
+		} else if err == io.EOF {
+			d.err = d.error(UnexpectedEOF, i)
+			return nil, d.err
+		} else if err != nil {
+			d.err = err
+			return nil, err
+		}
+	}
+
+	data := make([]byte, i-d.index)
+	copy(data, d.data[d.index:i])
+	d.index = i
+	return Unmarshal(data)
+}
+
+// More reports whether there is another JSON value in the input.
+func (d *Decoder) More() bool {
+	if d.err != nil {
+		return false
+	}
+	for {
+		for ; d.index < len(d.data); d.index++ {
+			switch d.data[d.index] {
+			case skipS, skipR, skipN, skipT:
+			default:
+				return true
+			}
+		}
+		if _, err := d.refill(); err != nil {
+			d.err = err
+			return false
+		}
+	}
+}
+
+// refill drops the data, that was already decoded, and reads the next part of the input.
+// It returns the count of dropped bytes.
+func (d *Decoder) refill() (dropped int, err error) {
+	if d.eof {
+		return 0, io.EOF
+	}
+	if d.index > 0 {
+		dropped = d.index
+		d.offset += dropped
+		for _, c := range d.data[:dropped] {
+			if c == skipN {
+				d.line++
+				d.column = 0
+			} else if utf8.RuneStart(c) {
+				d.column++
+			}
+		}
+		d.data = d.data[:copy(d.data, d.data[d.index:])]
+		d.index = 0
+	}
+	if cap(d.data)-len(d.data) < decoderMinRead {
+		data := make([]byte, len(d.data), 2*cap(d.data)+decoderMinRead)
+		copy(data, d.data)
+		d.data = data
+	}
+	n, err := d.reader.Read(d.data[len(d.data):cap(d.data)])
+	d.data = d.data[:len(d.data)+n]
+	if err == io.EOF {
+		d.eof = true
+		if n > 0 {
+			err = nil
+		}
+	}
+	return dropped, err
+}
+
+// error returns an error at the index of the current data, positioned in the whole stream
+func (d *Decoder) error(_type ErrorType, index int) error {
+	err := errorIn(d.data, _type, index).(Error)
+	err.Index += d.offset
+	if err.Line == 1 {
+		err.Column += d.column
+	}
+	err.Line += d.line
+	return err
+}
@@ -0,0 +1,204 @@
+package ajson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func ExampleDecoder() {
+	input := strings.NewReader(`{"name": "first"}
+{"name": "second"}
+[1, 2, 3] "string" null`)
+	decoder := NewDecoder(input)
+	for decoder.More() {
+		root, err := decoder.Decode()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(root.String())
+	}
+	// Output:
+	// {"name": "first"}
+	// {"name": "second"}
+	// [1, 2, 3]
+	// "string"
+	// null
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "empty", input: "", expected: []string{}},
+		{name: "whitespaces", input: " \r\n\t ", expected: []string{}},
+		{name: "single", input: `{"a":1}`, expected: []string{`{"a":1}`}},
+		{name: "numbers", input: "1 2.5 -3e4\n0", expected: []string{"1", "2.5", "-3e4", "0"}},
+		{name: "literals", input: "true false null", expected: []string{"true", "false", "null"}},
+		{name: "concatenated", input: `{}[]"a"{"b":[]}`, expected: []string{"{}", "[]", `"a"`, `{"b":[]}`}},
+		{name: "NDJSON", input: "{\"a\":1}\n{\"a\":2}\r\n{\"a\":3}\n", expected: []string{`{"a":1}`, `{"a":2}`, `{"a":3}`}},
+		{
+			name:     "multiline",
+			input:    "{\n  \"key\": [\n    1,\n    \"}\"\n  ]\n}\n[\n]",
+			expected: []string{"{\n  \"key\": [\n    1,\n    \"}\"\n  ]\n}", "[\n]"},
+		},
+		{name: "escaped", input: `"\"" "\\"`, expected: []string{`"\""`, `"\\"`}},
+		{name: "nested", input: `[[[{"a":[{}]}]]] 1`, expected: []string{`[[[{"a":[{}]}]]]`, "1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			readers := map[string]io.Reader{
+				"reader":   strings.NewReader(test.input),
+				"one byte": iotest.OneByteReader(strings.NewReader(test.input)),
+				"data err": iotest.DataErrReader(strings.NewReader(test.input)),
+			}
+			for name, reader := range readers {
+				decoder := NewDecoder(reader)
+				result := make([]string, 0)
+				for {
+					root, err := decoder.Decode()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("[%s] Decode() unexpected error: %s", name, err)
+					}
+					result = append(result, strings.TrimSpace(root.String()))
+				}
+				if len(result) != len(test.expected) {
+					t.Fatalf("[%s] Decode() wrong values count: %d != %d: %v", name, len(result), len(test.expected), result)
+				}
+				for i := range result {
+					if result[i] != test.expected[i] {
+						t.Errorf("[%s] Decode() wrong value #%d: %s != %s", name, i, result[i], test.expected[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		values int
+		_type  ErrorType
+		index  int
+	}{
+		{name: "wrong symbol", input: `{"a":1}x`, values: 1, _type: WrongSymbol, index: 7},
+		{name: "wrong close", input: `[1}`, values: 0, _type: WrongSymbol, index: 2},
+		{name: "wrong number", input: `1 01`, values: 1, _type: WrongSymbol, index: 3},
+		{name: "trailing comma", input: `[1,]`, values: 0, _type: WrongSymbol, index: 3},
+		{name: "missed colon", input: `{"a" 1}`, values: 0, _type: WrongSymbol, index: 5},
+		{name: "key in array", input: `["a":1]`, values: 0, _type: WrongSymbol, index: 4},
+		{name: "unexpected EOF", input: "{\"a\":1}\n[1,", values: 1, _type: UnexpectedEOF, index: 11},
+		{name: "unexpected EOF in string", input: `"abc`, values: 0, _type: UnexpectedEOF, index: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoder := NewDecoder(iotest.OneByteReader(strings.NewReader(test.input)))
+			for i := 0; i < test.values; i++ {
+				if _, err := decoder.Decode(); err != nil {
+					t.Fatalf("Decode() unexpected error: %s", err)
+				}
+			}
+			_, err := decoder.Decode()
+			var jerr Error
+			if !errors.As(err, &jerr) {
+				t.Fatalf("Decode() expected Error, got: %v", err)
+			}
+			if jerr.Type != test._type {
+				t.Errorf("Decode() wrong error type: %d != %d", jerr.Type, test._type)
+			}
+			if jerr.Index != test.index {
+				t.Errorf("Decode() wrong error index: %d != %d", jerr.Index, test.index)
+			}
+			if _, err2 := decoder.Decode(); err2 != err {
+				t.Errorf("Decode() error should be persistent: %v != %v", err2, err)
+			}
+			if decoder.More() {
+				t.Errorf("More() should be false after the error")
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_readerError(t *testing.T) {
+	expected := errors.New("test error")
+	decoder := NewDecoder(io.MultiReader(strings.NewReader(`{"a":1} [1, `), iotest.ErrReader(expected)))
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if _, err := decoder.Decode(); err != expected {
+		t.Errorf("Decode() wrong error: %v", err)
+	}
+}
+
+func TestDecoder_More(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(" 1 \n  "))
+	if !decoder.More() {
+		t.Fatalf("More() should be true")
+	}
+	root, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if root.MustNumeric() != 1 {
+		t.Errorf("Decode() wrong value: %s", root)
+	}
+	if decoder.More() {
+		t.Errorf("More() should be false")
+	}
+	if _, err = decoder.Decode(); err != io.EOF {
+		t.Errorf("Decode() expected io.EOF, got: %v", err)
+	}
+}
+
+func TestDecoder_Decode_large(t *testing.T) {
+	var input bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		_, _ = fmt.Fprintf(&input, `{"index":%d,"value":"%s"}`+"\n", i, strings.Repeat("x", i))
+	}
+	decoder := NewDecoder(&input)
+	for i := 0; i < 1000; i++ {
+		root, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("Decode() unexpected error: %s", err)
+		}
+		if index := root.MustKey("index").MustNumeric(); index != float64(i) {
+			t.Fatalf("Decode() wrong index: %v != %d", index, i)
+		}
+		if value := root.MustKey("value").MustString(); len(value) != i {
+			t.Fatalf("Decode() wrong value length: %d != %d", len(value), i)
+		}
+	}
+	if _, err := decoder.Decode(); err != io.EOF {
+		t.Errorf("Decode() expected io.EOF, got: %v", err)
+	}
+}
+
+func TestDecoder_Decode_errorPosition(t *testing.T) {
+	input := strings.Repeat("{\"a\": 1}\n", 200) + "{\"a\": 1} [\n  1,\n  x\n]"
+	decoder := NewDecoder(iotest.HalfReader(strings.NewReader(input)))
+	var err error
+	for err == nil {
+		_, err = decoder.Decode()
+	}
+	var jerr Error
+	if !errors.As(err, &jerr) {
+		t.Fatalf("Decode() expected Error, got: %v", err)
+	}
+	if jerr.Index != len(input)-3 || jerr.Line != 203 || jerr.Column != 3 {
+		t.Errorf("Decode() wrong error position: %s", jerr)
+	}
+	if jerr.Snippet != "  x\n  ^" {
+		t.Errorf("Decode() wrong snippet:\n%s", jerr.Snippet)
+	}
+}
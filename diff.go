@@ -0,0 +1,255 @@
+package ajson
+
+import (
+	"strconv"
+)
+
+// diffMaxCells is the maximum size of the table of the longest common subsequence, bigger arrays are compared by
+// the positions of the elements
+const diffMaxCells = 1 << 20
+
+// Operations of the Change
+const (
+	ChangeAdd     = "add"
+	ChangeRemove  = "remove"
+	ChangeReplace = "replace"
+)
+
+// Change is the single difference between two nodes, found by Diff. Old value is nil for the `add` operation, New
+// value is nil for the `remove` operation.
+type Change struct {
+	Op      string // One of ChangeAdd, ChangeRemove or ChangeReplace
+	Path    string // JSONPath of the location, e.g. `$['store']['book'][0]`
+	Pointer string // JSON Pointer of the location, e.g. `/store/book/0`
+	Old     *Node
+	New     *Node
+}
+
+// Diff returns the list of changes, that turns the node a into the node b. Objects are compared by their keys,
+// arrays are compared by the longest common subsequence of their elements, so inserted or removed element doesn't
+// change all the elements after it. Objects are visited in the order of Inheritors.
+//
+// The longest common subsequence takes O(n*m) time and memory, so if the arrays are too big (more than 2^20 pairs of
+// the elements, after the common prefix and suffix are skipped), their elements are compared by the positions.
+//
+// Changes are ordered: index of an array element is the index after the previous changes were applied, so the list
+// can be applied as JSON Patch, see Patch.
+func Diff(a, b *Node) []Change {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		return []Change{{Op: ChangeAdd, Path: "$", New: b}}
+	case b == nil:
+		return []Change{{Op: ChangeRemove, Path: "$", Old: a}}
+	}
+	return diff(nil, "$", "", a, b)
+}
+
+// Patch returns JSON Patch (RFC 6902) document, built from the list of changes, e.g. the result of Diff.
+func Patch(changes []Change) (*Node, error) {
+	result := ArrayNode("", []*Node{})
+	for _, change := range changes {
+		operation := ObjectNode("", map[string]*Node{})
+		err := operation.AppendObject("op", StringNode("", change.Op))
+		if err == nil {
+			err = operation.AppendObject("path", StringNode("", change.Pointer))
+		}
+		if err == nil && change.Op != ChangeRemove {
+			if change.New == nil {
+				return nil, errorRequest("change '%s' at '%s' has no value", change.Op, change.Pointer)
+			}
+			err = operation.AppendObject("value", change.New.Clone())
+		}
+		if err == nil {
+			err = result.AppendArray(operation)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// CreatePatch returns JSON Patch (RFC 6902) document, which turns the original node into the modified one, when
+// applied with ApplyPatch.
+func CreatePatch(original, modified *Node) (*Node, error) {
+	if original == nil || modified == nil {
+		return nil, errorUnparsed()
+	}
+	return Patch(Diff(original, modified))
+}
+
+// diff appends the changes between the nodes a and b, located at the path
+func diff(result []Change, path, pointer string, a, b *Node) []Change {
+	switch {
+	case a.IsObject() && b.IsObject():
+		return diffObjects(result, path, pointer, a, b)
+	case a.IsArray() && b.IsArray():
+		return diffArrays(result, path, pointer, a, b)
+	case equal(a, b):
+		return result
+	}
+	return append(result, Change{Op: ChangeReplace, Path: path, Pointer: pointer, Old: a, New: b})
+}
+
+// diffObjects appends the changes between the objects: removed and changed members first, then the added ones
+func diffObjects(result []Change, path, pointer string, a, b *Node) []Change {
+	for _, previous := range a.Inheritors() {
+		key := previous.Key()
+		childPath, childPointer := path+"['"+key+"']", pointer+"/"+pointerEscaper.Replace(key)
+		if value, ok := b.child(key); ok {
+			result = diff(result, childPath, childPointer, previous, value)
+		} else {
+			result = append(result, Change{Op: ChangeRemove, Path: childPath, Pointer: childPointer, Old: previous})
+		}
+	}
+	for _, value := range b.Inheritors() {
+		key := value.Key()
+		if _, ok := a.child(key); !ok {
+			result = append(result, Change{Op: ChangeAdd, Path: path + "['" + key + "']", Pointer: pointer + "/" + pointerEscaper.Replace(key), New: value})
+		}
+	}
+	return result
+}
+
+// diffArrays appends the changes between the arrays, based on the longest common subsequence of their elements
+func diffArrays(result []Change, path, pointer string, a, b *Node) []Change {
+	left, right := a.Inheritors(), b.Inheritors()
+	start := 0
+	for start < len(left) && start < len(right) && equal(left[start], right[start]) {
+		start++
+	}
+	end := 0
+	for end < len(left)-start && end < len(right)-start && equal(left[len(left)-1-end], right[len(right)-1-end]) {
+		end++
+	}
+	left, right = left[start:len(left)-end], right[start:len(right)-end]
+	if (len(left)+1)*(len(right)+1) > diffMaxCells {
+		result, _ = diffElements(result, path, pointer, start, left, right)
+		return result
+	}
+
+	// lengths[i][j] is the length of the longest common subsequence of left[i:] and right[j:]
+	n, m := len(left), len(right)
+	lengths := make([][]int, n+1)
+	same := make([][]bool, n)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		same[i] = make([]bool, m)
+		for j := m - 1; j >= 0; j-- {
+			if equal(left[i], right[j]) {
+				same[i][j] = true
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	index := start
+	for i, j := 0, 0; i < n || j < m; {
+		fromI, fromJ := i, j
+		for (i < n || j < m) && !(i < n && j < m && same[i][j]) {
+			if j == m || (i < n && lengths[i+1][j] >= lengths[i][j+1]) {
+				i++
+			} else {
+				j++
+			}
+		}
+		result, index = diffElements(result, path, pointer, index, left[fromI:i], right[fromJ:j])
+		if i < n && j < m {
+			i, j, index = i+1, j+1, index+1
+		}
+	}
+	return result
+}
+
+// diffElements appends the changes between the removed and the added elements of the array, found between the same
+// elements, starting from the index. Elements at the same positions are compared, the rest are removed or added.
+// Returns the index after the added elements.
+func diffElements(result []Change, path, pointer string, index int, removed, added []*Node) ([]Change, int) {
+	i := 0
+	for ; i < len(removed) && i < len(added); i++ {
+		result = diff(result, path+"["+strconv.Itoa(index)+"]", pointer+"/"+strconv.Itoa(index), removed[i], added[i])
+		index++
+	}
+	for _, previous := range removed[i:] {
+		result = append(result, Change{Op: ChangeRemove, Path: path + "[" + strconv.Itoa(index) + "]", Pointer: pointer + "/" + strconv.Itoa(index), Old: previous})
+	}
+	for _, value := range added[i:] {
+		result = append(result, Change{Op: ChangeAdd, Path: path + "[" + strconv.Itoa(index) + "]", Pointer: pointer + "/" + strconv.Itoa(index), New: value})
+		index++
+	}
+	return result, index
+}
+
+// equal returns true if the nodes have the same values, as Eq does, but the numbers are compared exactly, so the big
+// integers, that are the same as float64, are different. Nodes that can't be compared are not equal.
+func equal(a, b *Node) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Type() {
+	case Numeric:
+		return equalNumbers(a, b)
+	case Array:
+		left, right := a.Inheritors(), b.Inheritors()
+		if len(left) != len(right) {
+			return false
+		}
+		for i := range left {
+			if !equal(left[i], right[i]) {
+				return false
+			}
+		}
+		return true
+	case Object:
+		if a.Size() != b.Size() {
+			return false
+		}
+		for _, key := range a.Keys() {
+			left, _ := a.child(key)
+			right, ok := b.child(key)
+			if !ok || !equal(left, right) {
+				return false
+			}
+		}
+		return true
+	}
+	result, err := a.Eq(b)
+	return err == nil && result
+}
+
+// equalNumbers compares the numbers exactly
+func equalNumbers(a, b *Node) bool {
+	if result, err := a.Eq(b); err != nil || !result {
+		// different float64 values can't be the same exactly
+		return false
+	}
+	left, err := a.GetNumberString()
+	if err != nil {
+		return false
+	}
+	right, err := b.GetNumberString()
+	if err != nil {
+		return false
+	}
+	if left == right {
+		return true
+	}
+	x, err := a.GetBigFloat()
+	if err != nil {
+		// NaN and Infinity of JSON5
+		return true
+	}
+	y, err := b.GetBigFloat()
+	if err != nil {
+		return true
+	}
+	return x.Cmp(y) == 0
+}
@@ -0,0 +1,201 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleDiff() {
+	a := Must(Unmarshal([]byte(`{"name": "ajson", "tags": ["json", "path"], "stars": 1}`)))
+	b := Must(Unmarshal([]byte(`{"name": "ajson", "tags": ["go", "json", "path"], "forks": 2}`)))
+	changes := Diff(a, b)
+	for _, change := range changes {
+		fmt.Println(change.Op, change.Path)
+	}
+	patch, err := Patch(changes)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(patch)
+	// Output:
+	// remove $['stars']
+	// add $['tags'][0]
+	// add $['forks']
+	// [{"op":"remove","path":"/stars"},{"op":"add","path":"/tags/0","value":"go"},{"op":"add","path":"/forks","value":2}]
+}
+
+func TestDiff(t *testing.T) {
+	type change struct {
+		op, path, pointer, old, new string
+	}
+	tests := []struct {
+		name     string
+		a, b     string
+		expected []change
+	}{
+		{name: "equal", a: `{"a": [1, {"b": 2.0}], "c": null}`, b: `{"c": null, "a": [1, {"b": 2}]}`, expected: []change{}},
+		{name: "scalar", a: `1`, b: `"1"`, expected: []change{{"replace", "$", "", `1`, `"1"`}}},
+		{name: "type", a: `{"a": []}`, b: `{"a": {}}`, expected: []change{{"replace", "$['a']", "/a", `[]`, `{}`}}},
+		{name: "object", a: `{"a": 1, "b": {"c": 2, "d": 3}, "e~/": 4}`, b: `{"b": {"c": 2, "d": 5}, "e~/": 4, "f": 6}`, expected: []change{
+			{"remove", "$['a']", "/a", `1`, ``},
+			{"replace", "$['b']['d']", "/b/d", `3`, `5`},
+			{"add", "$['f']", "/f", ``, `6`},
+		}},
+		{name: "escaped", a: `{"e~/": 4}`, b: `{"e~/": 5}`, expected: []change{{"replace", "$['e~/']", "/e~0~1", `4`, `5`}}},
+		{name: "insert", a: `[1, 2, 3, 4, 5]`, b: `[1, 2, 3, 10, 4, 5]`, expected: []change{{"add", "$[3]", "/3", ``, `10`}}},
+		{name: "delete", a: `[1, 2, 3, 4, 5]`, b: `[1, 3, 4, 5]`, expected: []change{{"remove", "$[1]", "/1", `2`, ``}}},
+		{name: "insert_many", a: `[1, 2, 3]`, b: `[0, 1, 2, 2.5, 3, 4]`, expected: []change{
+			{"add", "$[0]", "/0", ``, `0`},
+			{"add", "$[3]", "/3", ``, `2.5`},
+			{"add", "$[5]", "/5", ``, `4`},
+		}},
+		{name: "delete_many", a: `[0, 1, 2, 3, 4, 5]`, b: `[1, 4]`, expected: []change{
+			{"remove", "$[0]", "/0", `0`, ``},
+			{"remove", "$[1]", "/1", `2`, ``},
+			{"remove", "$[1]", "/1", `3`, ``},
+			{"remove", "$[2]", "/2", `5`, ``},
+		}},
+		{name: "element", a: `[1, {"a": 1}, 3]`, b: `[1, {"a": 2}, 3]`, expected: []change{{"replace", "$[1]['a']", "/1/a", `1`, `2`}}},
+		{name: "elements", a: `[1, 2, 3]`, b: `[4, 5]`, expected: []change{
+			{"replace", "$[0]", "/0", `1`, `4`},
+			{"replace", "$[1]", "/1", `2`, `5`},
+			{"remove", "$[2]", "/2", `3`, ``},
+		}},
+		{name: "move", a: `["a", "b", "c"]`, b: `["c", "a", "b"]`, expected: []change{
+			{"add", "$[0]", "/0", ``, `"c"`},
+			{"remove", "$[3]", "/3", `"c"`, ``},
+		}},
+		{name: "big number", a: `{"id": 12345678901234567890}`, b: `{"id": 12345678901234567891}`, expected: []change{
+			{"replace", "$['id']", "/id", `12345678901234567890`, `12345678901234567891`},
+		}},
+		{name: "big element", a: `[12345678901234567890]`, b: `[12345678901234567891]`, expected: []change{
+			{"replace", "$[0]", "/0", `12345678901234567890`, `12345678901234567891`},
+		}},
+		{name: "same number", a: `[1e20, 0.10, -0]`, b: `[100000000000000000000, 1e-1, 0]`, expected: []change{}},
+		{name: "nested", a: `{"a": [[1, 2], [3]]}`, b: `{"a": [[1, 2, 3], [3]]}`, expected: []change{{"add", "$['a'][0][2]", "/a/0/2", ``, `3`}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				a := Must(UnmarshalWithOptions([]byte(test.a), Options{Lazy: lazy}))
+				b := Must(UnmarshalWithOptions([]byte(test.b), Options{Lazy: lazy}))
+				result := Diff(a, b)
+				if len(result) != len(test.expected) {
+					t.Fatalf("Diff() wrong result: %v", result)
+				}
+				for i, expected := range test.expected {
+					actual := change{result[i].Op, result[i].Path, result[i].Pointer, "", ""}
+					if result[i].Old != nil {
+						actual.old = result[i].Old.String()
+					}
+					if result[i].New != nil {
+						actual.new = result[i].New.String()
+					}
+					if actual != expected {
+						t.Errorf("Diff() wrong change %d: %v, expected %v", i, actual, expected)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_nil(t *testing.T) {
+	node := NumericNode("", 1)
+	if result := Diff(nil, nil); len(result) != 0 {
+		t.Errorf("Diff() wrong result: %v", result)
+	}
+	if result := Diff(nil, node); len(result) != 1 || result[0].Op != ChangeAdd || result[0].New != node {
+		t.Errorf("Diff() wrong result: %v", result)
+	}
+	if result := Diff(node, nil); len(result) != 1 || result[0].Op != ChangeRemove || result[0].Old != node {
+		t.Errorf("Diff() wrong result: %v", result)
+	}
+}
+
+func TestDiff_big(t *testing.T) {
+	const size = 2000
+	a, b := ArrayNode("", nil), ArrayNode("", nil)
+	if err := b.AppendArray(StringNode("", "first")); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	for i := 0; i < size; i++ {
+		if err := a.AppendArray(NumericNode("", float64(i))); err != nil {
+			t.Fatalf("AppendArray() unexpected error: %s", err)
+		}
+		if err := b.AppendArray(NumericNode("", float64(i))); err != nil {
+			t.Fatalf("AppendArray() unexpected error: %s", err)
+		}
+	}
+	if err := b.AppendArray(StringNode("", "last")); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	if err := b.DeleteIndex(size); err != nil {
+		t.Fatalf("DeleteIndex() unexpected error: %s", err)
+	}
+	// elements are compared by the positions: size of the table is more than diffMaxCells
+	changes := Diff(a, b)
+	if len(changes) != size+1 || changes[0].Op != ChangeReplace || changes[size].Op != ChangeAdd {
+		t.Fatalf("Diff() wrong result: %d changes", len(changes))
+	}
+	patch, err := Patch(changes)
+	if err != nil {
+		t.Fatalf("Patch() unexpected error: %s", err)
+	}
+	if err = ApplyPatch(a, patch); err != nil {
+		t.Fatalf("ApplyPatch() unexpected error: %s", err)
+	}
+	if !equal(a, b) {
+		t.Errorf("ApplyPatch() wrong result")
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		original string
+		modified string
+	}{
+		{original: `{"a": 1}`, modified: `{"a": 1}`},
+		{original: `{"a": 1}`, modified: `[1]`},
+		{original: `{"a": {"b": [1, 2, 3]}, "c": "d"}`, modified: `{"a": {"b": [3, 2, 1], "e": null}}`},
+		{original: `[1, 2, 3, 4, 5, 6, 7, 8]`, modified: `[0, 2, 4, 6, 8, 9]`},
+		{original: `[{"id": 1}, {"id": 2}, {"id": 3}]`, modified: `[{"id": 1}, {"id": 2, "name": "two"}, {"id": 4}, {"id": 3}]`},
+		{original: `["a", "b", "c", "d", "e", "f"]`, modified: `["f", "e", "d", "c", "b", "a"]`},
+		{original: `[[1, 2], [3, 4]]`, modified: `[[4, 3], [], [2, 1]]`},
+		{original: `[]`, modified: `[null, true, {"": ""}]`},
+		{original: `{"a~b": {"c/d": [1]}}`, modified: `{"a~b": {"c/d": [1, 2]}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.original+" -> "+test.modified, func(t *testing.T) {
+			original := Must(Unmarshal([]byte(test.original)))
+			modified := Must(Unmarshal([]byte(test.modified)))
+			patch, err := CreatePatch(original, modified)
+			if err != nil {
+				t.Fatalf("CreatePatch() unexpected error: %s", err)
+			}
+			data, err := Marshal(patch)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if err = ApplyPatch(original, Must(Unmarshal(data))); err != nil {
+				t.Fatalf("ApplyPatch() unexpected error: %s for %s", err, patch)
+			}
+			if ok, err := original.Eq(modified); err != nil || !ok {
+				t.Errorf("ApplyPatch() wrong result %s for %s", original, patch)
+			}
+		})
+	}
+	if _, err := CreatePatch(nil, NullNode("")); err == nil {
+		t.Errorf("CreatePatch() expected error")
+	}
+}
+
+func TestPatch_errors(t *testing.T) {
+	_, err := Patch([]Change{{Op: ChangeReplace, Path: "$['a']", Pointer: "/a"}})
+	if err == nil {
+		t.Fatalf("Patch() expected error")
+	}
+	if err.Error() != "wrong request: change 'replace' at '/a' has no value" {
+		t.Errorf("Patch() wrong error: %s", err)
+	}
+}
@@ -1,90 +1,433 @@
 package ajson
 
 import (
+	"io"
+	"math"
+	"sort"
 	"strconv"
 )
 
+// MarshalOptions is a set of options for the MarshalWithOptions.
+//
+// Untouched containers are copied from the source as is, only if the options don't change their layout: so any of
+// the options below will re-encode the whole tree.
+type MarshalOptions struct {
+	// SortKeys emits keys of objects in the sorted order, instead of the insertion order.
+	SortKeys bool
+	// Prefix begins each new line of the indented output.
+	Prefix string
+	// Indent is added for each level of nesting, e.g. "\t" or "    ". Output is compact, if Prefix and Indent are empty.
+	Indent string
+	// SpaceAfterColon adds a space between the key and the value of an object.
+	SpaceAfterColon bool
+
+	// EscapeHTML escapes '<', '>' and '&' in the encoded strings, as \u003c, \u003e and \u0026. Untouched strings
+	// are copied from the source as is, unless ASCIIOnly or EscapeSlash is set.
+	EscapeHTML bool
+	// ASCIIOnly escapes all non-ASCII runes as \uXXXX, with the surrogate pairs for the runes out of the BMP.
+	ASCIIOnly bool
+	// EscapeSlash escapes '/' as '\/'.
+	EscapeSlash bool
+
+	// PreserveFormat keeps the whitespaces and layout of the source in the changed containers, so only the changed
+	// values are replaced in the original data. New values get the layout of the last original value of the container.
+	// It has no effect together with the layout and escaping options.
+	PreserveFormat bool
+}
+
 // Marshal returns slice of bytes, marshaled from current value
 func Marshal(node *Node) (result []byte, err error) {
-	result = make([]byte, 0)
+	return MarshalWithOptions(node, MarshalOptions{EscapeHTML: true})
+}
+
+// MarshalIndent is like Marshal but applies Indent to format the output, in the same way as json.MarshalIndent:
+// each element begins on a new line, that starts with prefix followed by one or more copies of indent.
+func MarshalIndent(node *Node, prefix, indent string) (result []byte, err error) {
+	return MarshalWithOptions(node, MarshalOptions{
+		Prefix:          prefix,
+		Indent:          indent,
+		SpaceAfterColon: true,
+		EscapeHTML:      true,
+	})
+}
+
+// MarshalWithOptions returns slice of bytes, marshaled from current value with the given options
+func MarshalWithOptions(node *Node, options MarshalOptions) (result []byte, err error) {
+	m := &marshaler{
+		options: options,
+		result:  make([]byte, 0),
+	}
+	if err = m.marshal(node); err != nil {
+		return nil, err
+	}
+	return m.result, nil
+}
+
+// marshaler accumulates the marshaled value of the nodes. If writer is set, result is flushed to it every time,
+// when it grows over the encoderBufferSize.
+type marshaler struct {
+	options MarshalOptions
+	result  []byte
+	depth   int
+	writer  io.Writer
+}
+
+// write appends data to the result, large data is written directly to the writer
+func (m *marshaler) write(data []byte) error {
+	if m.writer != nil && len(m.result)+len(data) > encoderBufferSize {
+		if err := m.flush(); err != nil {
+			return err
+		}
+		if len(data) > encoderBufferSize {
+			_, err := m.writer.Write(data)
+			return err
+		}
+	}
+	m.result = append(m.result, data...)
+	return nil
+}
+
+// flush writes the result to the writer
+func (m *marshaler) flush() error {
+	if m.writer == nil || len(m.result) == 0 {
+		return nil
+	}
+	_, err := m.writer.Write(m.result)
+	m.result = m.result[:0]
+	return err
+}
+
+// grown flushes the result, if it's bigger than the encoderBufferSize
+func (m *marshaler) grown() error {
+	if m.writer != nil && len(m.result) >= encoderBufferSize {
+		return m.flush()
+	}
+	return nil
+}
+
+// pretty returns true if output should be indented
+func (m *marshaler) pretty() bool {
+	return m.options.Prefix != "" || m.options.Indent != ""
+}
+
+// reformat returns true if untouched containers should be re-encoded
+func (m *marshaler) reformat() bool {
+	return m.options.SortKeys || m.options.SpaceAfterColon || m.pretty() || m.escape()
+}
+
+// escape returns true if untouched strings should be re-encoded
+func (m *marshaler) escape() bool {
+	return m.options.ASCIIOnly || m.options.EscapeSlash
+}
+
+// quote escapes the string with the options
+func (m *marshaler) quote(value string) []byte {
+	return quoteString(value, m.options.EscapeHTML, m.options.ASCIIOnly, m.options.EscapeSlash)
+}
+
+// newline begins the new line with the prefix and indent for the current depth
+func (m *marshaler) newline() {
+	if !m.pretty() {
+		return
+	}
+	m.result = append(m.result, '\n')
+	m.result = append(m.result, m.options.Prefix...)
+	for i := 0; i < m.depth; i++ {
+		m.result = append(m.result, m.options.Indent...)
+	}
+}
+
+// marshal appends marshaled value of the node to the result
+func (m *marshaler) marshal(node *Node) (err error) {
 	var (
 		sValue string
 		bValue bool
-		nValue float64
-		oValue []byte
 	)
 
 	if node == nil {
-		return nil, errorUnparsed()
-	} else if node.dirty {
+		return errorUnparsed()
+	} else if node.dirty || node.lenient || (node.ready() && ((m.reformat() && node.isContainer()) || (m.escape() && node.IsString()))) {
+		node.load()
+		if m.options.PreserveFormat && !m.reformat() && node.isContainer() && node.data != nil && node.ready() {
+			if layout, ok := newLayout(node); ok {
+				return m.splice(node, layout)
+			}
+		}
 		switch node._type {
 		case Null:
-			result = append(result, _null...)
+			m.result = append(m.result, _null...)
 		case Numeric:
-			nValue, err = node.GetNumeric()
+			if node.lenient {
+				sValue, err = json5Number(node)
+			} else {
+				sValue, err = node.GetNumberString()
+			}
 			if err != nil {
-				return nil, err
+				return err
 			}
-			result = append(result, strconv.FormatFloat(nValue, 'g', -1, 64)...)
+			m.result = append(m.result, sValue...)
 		case String:
 			sValue, err = node.GetString()
 			if err != nil {
-				return nil, err
+				return err
+			}
+			m.result = append(m.result, quotes)
+			if err = m.write(m.quote(sValue)); err != nil {
+				return err
 			}
-			result = append(result, quotes)
-			result = append(result, quoteString(sValue, true)...)
-			result = append(result, quotes)
+			m.result = append(m.result, quotes)
 		case Bool:
 			bValue, err = node.GetBool()
 			if err != nil {
-				return nil, err
+				return err
 			} else if bValue {
-				result = append(result, _true...)
+				m.result = append(m.result, _true...)
 			} else {
-				result = append(result, _false...)
+				m.result = append(m.result, _false...)
 			}
 		case Array:
-			result = append(result, bracketL)
+			m.result = append(m.result, bracketL)
+			m.depth++
 			for i := 0; i < len(node.children); i++ {
 				if i != 0 {
-					result = append(result, coma)
+					m.result = append(m.result, coma)
 				}
 				child, ok := node.children[strconv.Itoa(i)]
 				if !ok {
-					return nil, errorRequest("wrong length of array")
+					return errorRequest("wrong length of array")
 				}
-				oValue, err = Marshal(child)
-				if err != nil {
-					return nil, err
+				m.newline()
+				if err = m.marshal(child); err != nil {
+					return err
 				}
-				result = append(result, oValue...)
+				if err = m.grown(); err != nil {
+					return err
+				}
+			}
+			m.depth--
+			if len(node.children) != 0 {
+				m.newline()
 			}
-			result = append(result, bracketR)
+			m.result = append(m.result, bracketR)
 		case Object:
-			result = append(result, bracesL)
-			bValue = false
-			for key, child := range node.children {
-				if bValue {
-					result = append(result, coma)
-				} else {
-					bValue = true
+			keys := node.keys
+			if m.options.SortKeys {
+				keys = make([]string, len(node.keys))
+				copy(keys, node.keys)
+				sort.Strings(keys)
+			}
+			m.result = append(m.result, bracesL)
+			m.depth++
+			for i, key := range keys {
+				if i != 0 {
+					m.result = append(m.result, coma)
+				}
+				m.newline()
+				m.result = append(m.result, quotes)
+				m.result = append(m.result, m.quote(key)...)
+				m.result = append(m.result, quotes, colon)
+				if m.options.SpaceAfterColon {
+					m.result = append(m.result, skipS)
+				}
+				if err = m.marshal(node.children[key]); err != nil {
+					return err
 				}
-				result = append(result, quotes)
-				result = append(result, quoteString(key, true)...)
-				result = append(result, quotes, colon)
-				oValue, err = Marshal(child)
-				if err != nil {
-					return nil, err
+				if err = m.grown(); err != nil {
+					return err
 				}
-				result = append(result, oValue...)
 			}
-			result = append(result, bracesR)
+			m.depth--
+			if len(keys) != 0 {
+				m.newline()
+			}
+			m.result = append(m.result, bracesR)
 		}
 	} else if node.ready() {
-		result = append(result, node.Source()...)
+		return m.write(node.Source())
+	} else {
+		return errorUnparsed()
+	}
+
+	return nil
+}
+
+// layout is the original formatting of the container
+type layout struct {
+	entries []entryLayout
+	keys    map[string]int
+	// closing is the whitespaces before the closing bracket
+	closing []byte
+}
+
+// entryLayout is the original formatting of the value in the container
+type entryLayout struct {
+	// prefix is the whitespaces before the value (or its key)
+	prefix []byte
+	// key is the original quoted key of an object
+	key []byte
+	// infix is the colon with whitespaces between key and value of an object
+	infix []byte
+	// suffix is the whitespaces after the value
+	suffix []byte
+}
+
+// newLayout scans the source of the container for its layout. It returns false if source can't be scanned.
+func newLayout(node *Node) (result *layout, ok bool) {
+	data := (*node.data)[:node.borders[1]]
+	tokenizer := &Tokenizer{
+		data:  data,
+		index: node.borders[0],
+	}
+	result = &layout{
+		keys: make(map[string]int),
+	}
+	var (
+		entry    entryLayout
+		key      string
+		keyEnd   int
+		boundary = node.borders[0] + 1
+	)
+	for {
+		token, err := tokenizer.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, false
+		}
+		if token.Depth != 1 {
+			continue
+		}
+		switch token.Type {
+		case TokenKey:
+			entry.prefix = data[boundary:token.Start]
+			entry.key = token.Raw
+			key, _ = unquote(token.Raw, quotes)
+			keyEnd = token.End
+			continue
+		case TokenObjectStart, TokenArrayStart:
+			if node.IsObject() {
+				entry.infix = data[keyEnd:token.Start]
+			} else {
+				entry.prefix = data[boundary:token.Start]
+			}
+			continue
+		case TokenObjectEnd, TokenArrayEnd:
+		default:
+			if node.IsObject() {
+				entry.infix = data[keyEnd:token.Start]
+			} else {
+				entry.prefix = data[boundary:token.Start]
+			}
+		}
+		// end of the value
+		boundary = token.End
+		for boundary < len(data) && isSpace(data[boundary]) {
+			boundary++
+		}
+		entry.suffix = data[token.End:boundary]
+		boundary++
+		if node.IsObject() {
+			result.keys[key] = len(result.entries)
+		}
+		result.entries = append(result.entries, entry)
+		entry = entryLayout{}
+	}
+	if size := len(result.entries); size == 0 {
+		result.closing = data[node.borders[0]+1 : node.borders[1]-1]
 	} else {
-		return nil, errorUnparsed()
+		result.closing = result.entries[size-1].suffix
+		result.entries[size-1].suffix = nil
 	}
+	return result, true
+}
+
+// entry returns the layout of the value by its index or key, new values get the layout of the last one
+func (l *layout) entry(index int, key *string) entryLayout {
+	if key != nil {
+		if i, ok := l.keys[*key]; ok {
+			return l.entries[i]
+		}
+	} else if index < len(l.entries) {
+		return l.entries[index]
+	}
+	if len(l.entries) == 0 {
+		return entryLayout{infix: []byte{colon}}
+	}
+	entry := l.entries[len(l.entries)-1]
+	entry.key = nil
+	entry.suffix = nil
+	return entry
+}
+
+// splice appends the container with its original layout
+func (m *marshaler) splice(node *Node, layout *layout) (err error) {
+	size := len(node.children)
+	if node.IsArray() {
+		m.result = append(m.result, bracketL)
+	} else {
+		m.result = append(m.result, bracesL)
+	}
+	for i := 0; i < size; i++ {
+		var (
+			child *Node
+			entry entryLayout
+		)
+		if node.IsArray() {
+			child = node.children[strconv.Itoa(i)]
+			entry = layout.entry(i, nil)
+		} else {
+			child = node.children[node.keys[i]]
+			entry = layout.entry(i, &node.keys[i])
+		}
+		if child == nil {
+			return errorRequest("wrong length of array")
+		}
+		m.result = append(m.result, entry.prefix...)
+		if node.IsObject() {
+			if entry.key != nil {
+				m.result = append(m.result, entry.key...)
+			} else {
+				m.result = append(m.result, quotes)
+				m.result = append(m.result, m.quote(node.keys[i])...)
+				m.result = append(m.result, quotes)
+			}
+			m.result = append(m.result, entry.infix...)
+		}
+		if err = m.marshal(child); err != nil {
+			return err
+		}
+		if i != size-1 {
+			m.result = append(m.result, entry.suffix...)
+			m.result = append(m.result, coma)
+		}
+		if err = m.grown(); err != nil {
+			return err
+		}
+	}
+	m.result = append(m.result, layout.closing...)
+	if node.IsArray() {
+		m.result = append(m.result, bracketR)
+	} else {
+		m.result = append(m.result, bracesR)
+	}
+	return nil
+}
 
-	return
+// formatFloat returns the shortest representation of the number, in the same way as encoding/json does:
+// exponent is used only for the very small and the very large numbers.
+func formatFloat(value float64) []byte {
+	abs := math.Abs(value)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	result := strconv.AppendFloat(nil, value, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if n := len(result); n >= 4 && result[n-4] == 'e' && result[n-3] == '-' && result[n-2] == '0' {
+			result[n-2] = result[n-1]
+			result = result[:n-1]
+		}
+	}
+	return result
 }
@@ -1,6 +1,8 @@
 package ajson
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 )
@@ -73,6 +75,30 @@ func TestMarshal_Primitive(t *testing.T) {
 			name: "100.5",
 			node: NumericNode("", 100.5),
 		},
+		{
+			name: "1234567",
+			node: NumericNode("", 1234567),
+		},
+		{
+			name: "100000000000000000000",
+			node: NumericNode("", 1e20),
+		},
+		{
+			name: "1e+21",
+			node: NumericNode("", 1e21),
+		},
+		{
+			name: "0.000001",
+			node: NumericNode("", 1e-6),
+		},
+		{
+			name: "-1.5e-7",
+			node: NumericNode("", -1.5e-7),
+		},
+		{
+			name: "1e-100",
+			node: NumericNode("", 1e-100),
+		},
 		{
 			name: "[1,2,3]",
 			node: ArrayNode("", []*Node{
@@ -194,3 +220,334 @@ func TestMarshal_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshal_NumberString(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"id":1,"big":12345678901234567890123}`)))
+	if err := root.MustKey("id").SetInt64(1234567890123456789); err != nil {
+		t.Fatalf("SetInt64() unexpected error: %s", err)
+	}
+	if err := root.AppendObject("value", NumericNode("", 0)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.MustKey("value").SetNumberString("-1.50e+300"); err != nil {
+		t.Fatalf("SetNumberString() unexpected error: %s", err)
+	}
+	result := Must(Unmarshal(mustBytes(Marshal(root))))
+	expected := map[string]string{"id": "1234567890123456789", "big": "12345678901234567890123", "value": "-1.50e+300"}
+	for key, value := range expected {
+		if got := string(result.MustKey(key).Source()); got != value {
+			t.Errorf("Marshal() wrong value of %s: %s", key, got)
+		}
+	}
+}
+
+func TestMarshal_keysOrder(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"z":1,"a":{"y":2,"b":3},"m":4}`)))
+	if err := root.AppendObject("c", StringNode("", "5")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.AppendObject("z", NullNode("")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.MustKey("a").AppendObject("a", BoolNode("", true)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.DeleteKey("m"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	expected := `{"z":null,"a":{"y":2,"b":3,"a":true},"c":"5"}`
+	for i := 0; i < 10; i++ {
+		if result := string(mustBytes(Marshal(root))); result != expected {
+			t.Fatalf("Marshal() wrong result: %s", result)
+		}
+	}
+	if result := string(mustBytes(Marshal(root.Clone()))); result != expected {
+		t.Errorf("Marshal() wrong result of clone: %s", result)
+	}
+}
+
+func TestMarshalWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		options  MarshalOptions
+		expected string
+	}{
+		{name: "default", input: `{"b":1, "a":[{"d":2, "c":3}]}`, expected: `{"b":1, "a":[{"d":2, "c":3}]}`},
+		{name: "sort keys", input: `{"b":1, "a":[{"d":2, "c":3}]}`, options: MarshalOptions{SortKeys: true}, expected: `{"a":[{"c":3,"d":2}],"b":1}`},
+		{name: "sort scalar", input: ` "b" `, options: MarshalOptions{SortKeys: true}, expected: `"b"`},
+		{name: "space after colon", input: `{"b":1, "a":[{"d":2, "c":3}]}`, options: MarshalOptions{SpaceAfterColon: true}, expected: `{"b": 1,"a": [{"d": 2,"c": 3}]}`},
+		{name: "indent", input: `{"b":1, "a":[{"d":2, "c":3}, [], {}]}`, options: MarshalOptions{Indent: "\t"}, expected: "{\n\t\"b\":1,\n\t\"a\":[\n\t\t{\n\t\t\t\"d\":2,\n\t\t\t\"c\":3\n\t\t},\n\t\t[],\n\t\t{}\n\t]\n}"},
+		{name: "prefix", input: `[1,[2]]`, options: MarshalOptions{Prefix: "//"}, expected: "[\n//1,\n//[\n//2\n//]\n//]"},
+		{name: "html clean", input: `["<a&b>"]`, options: MarshalOptions{EscapeHTML: true}, expected: `["<a&b>"]`},
+		{name: "ascii only", input: `{"ключ":"значение 😀 \u00e9", "a":1}`, options: MarshalOptions{ASCIIOnly: true}, expected: `{"\u043a\u043b\u044e\u0447":"\u0437\u043d\u0430\u0447\u0435\u043d\u0438\u0435 \ud83d\ude00 \u00e9","a":1}`},
+		{name: "escape slash", input: `{"a/b":"</script>"}`, options: MarshalOptions{EscapeSlash: true}, expected: `{"a\/b":"<\/script>"}`},
+		{name: "escape all", input: `["</a>", "é"]`, options: MarshalOptions{EscapeHTML: true, ASCIIOnly: true, EscapeSlash: true}, expected: `["\u003c\/a\u003e","\u00e9"]`},
+		{name: "all", input: `{"b":1, "a":{"d":2, "c":3}}`, options: MarshalOptions{SortKeys: true, Prefix: ">", Indent: "  ", SpaceAfterColon: true}, expected: "{\n>  \"a\": {\n>    \"c\": 3,\n>    \"d\": 2\n>  },\n>  \"b\": 1\n>}"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := MarshalWithOptions(Must(Unmarshal([]byte(test.input))), test.options)
+			if err != nil {
+				t.Fatalf("MarshalWithOptions() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("MarshalWithOptions() wrong result: %s", result)
+			}
+		})
+	}
+}
+
+func TestMarshalWithOptions_escape(t *testing.T) {
+	root := ArrayNode("", []*Node{StringNode("", "<a href=\"/\">\u2028 & 😀</a>")})
+	tests := []struct {
+		name     string
+		options  MarshalOptions
+		expected string
+	}{
+		{name: "none", expected: `["<a href=\"/\">\u2028 & 😀</a>"]`},
+		{name: "html", options: MarshalOptions{EscapeHTML: true}, expected: `["\u003ca href=\"/\"\u003e\u2028 \u0026 😀\u003c/a\u003e"]`},
+		{name: "ascii", options: MarshalOptions{ASCIIOnly: true}, expected: `["<a href=\"/\">\u2028 & \ud83d\ude00</a>"]`},
+		{name: "slash", options: MarshalOptions{EscapeSlash: true}, expected: `["<a href=\"\/\">\u2028 & 😀<\/a>"]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := string(mustBytes(MarshalWithOptions(root, test.options)))
+			if result != test.expected {
+				t.Errorf("MarshalWithOptions() wrong result: %s", result)
+			}
+			if value := Must(Unmarshal([]byte(result))).MustIndex(0).MustString(); value != root.MustIndex(0).MustString() {
+				t.Errorf("MarshalWithOptions() wrong value: %s", value)
+			}
+		})
+	}
+	if result := string(mustBytes(Marshal(root))); result != `["\u003ca href=\"/\"\u003e\u2028 \u0026 😀\u003c/a\u003e"]` {
+		t.Errorf("Marshal() should escape HTML: %s", result)
+	}
+}
+
+func ExampleMarshalOptions_preserveFormat() {
+	root := Must(Unmarshal([]byte(`{
+    "name": "service",
+    "port": 8080,
+    "tags": [ "a", "b" ]
+}`)))
+	if err := root.MustKey("port").SetNumeric(9090); err != nil {
+		panic(err)
+	}
+	if err := root.MustKey("tags").AppendArray(StringNode("", "c")); err != nil {
+		panic(err)
+	}
+	result, err := MarshalWithOptions(root, MarshalOptions{PreserveFormat: true, EscapeHTML: true})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(result))
+	// Output:
+	// {
+	//     "name": "service",
+	//     "port": 9090,
+	//     "tags": [ "a", "b", "c" ]
+	// }
+}
+
+func TestMarshalWithOptions_preserveFormat(t *testing.T) {
+	source := `{
+	"name" : "service",
+	"a\u0062" : { "x": 1 ,
+	               "y": [1,  2,  3] },
+	"list": [
+		{"id": 1},
+		{"id": 2}
+	],
+	"empty": [ ],
+	"last": null
+}`
+	tests := []struct {
+		name     string
+		update   func(root *Node) error
+		expected string
+	}{
+		{
+			name:     "untouched",
+			update:   func(root *Node) error { return nil },
+			expected: source,
+		},
+		{
+			name: "scalar",
+			update: func(root *Node) error {
+				return root.MustKey("ab").MustKey("y").MustIndex(1).SetString("two")
+			},
+			expected: `{
+	"name" : "service",
+	"a\u0062" : { "x": 1 ,
+	               "y": [1,  "two",  3] },
+	"list": [
+		{"id": 1},
+		{"id": 2}
+	],
+	"empty": [ ],
+	"last": null
+}`,
+		},
+		{
+			name: "append",
+			update: func(root *Node) error {
+				if err := root.MustKey("list").AppendArray(Must(Unmarshal([]byte(`{"id": 3}`)))); err != nil {
+					return err
+				}
+				if err := root.MustKey("empty").AppendArray(NullNode("")); err != nil {
+					return err
+				}
+				return root.AppendObject("new", BoolNode("", true))
+			},
+			expected: `{
+	"name" : "service",
+	"a\u0062" : { "x": 1 ,
+	               "y": [1,  2,  3] },
+	"list": [
+		{"id": 1},
+		{"id": 2},
+		{"id": 3}
+	],
+	"empty": [null ],
+	"last": null,
+	"new": true
+}`,
+		},
+		{
+			name: "delete",
+			update: func(root *Node) error {
+				if err := root.DeleteKey("last"); err != nil {
+					return err
+				}
+				if err := root.MustKey("ab").DeleteKey("x"); err != nil {
+					return err
+				}
+				return root.MustKey("list").DeleteIndex(0)
+			},
+			expected: `{
+	"name" : "service",
+	"a\u0062" : {
+	               "y": [1,  2,  3] },
+	"list": [
+		{"id": 2}
+	],
+	"empty": [ ]
+}`,
+		},
+		{
+			name: "replace container",
+			update: func(root *Node) error {
+				return root.MustKey("list").SetNode(Must(Unmarshal([]byte(`[ 1 ]`))))
+			},
+			expected: `{
+	"name" : "service",
+	"a\u0062" : { "x": 1 ,
+	               "y": [1,  2,  3] },
+	"list": [ 1 ],
+	"empty": [ ],
+	"last": null
+}`,
+		},
+		{
+			name: "new container",
+			update: func(root *Node) error {
+				return root.MustKey("last").SetArray([]*Node{NumericNode("", 1), NumericNode("", 2)})
+			},
+			expected: `{
+	"name" : "service",
+	"a\u0062" : { "x": 1 ,
+	               "y": [1,  2,  3] },
+	"list": [
+		{"id": 1},
+		{"id": 2}
+	],
+	"empty": [ ],
+	"last": [1,2]
+}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, options := range []Options{{}, {Lazy: true}} {
+				root := Must(UnmarshalWithOptions([]byte(source), options))
+				if err := test.update(root); err != nil {
+					t.Fatalf("update unexpected error: %s", err)
+				}
+				result, err := MarshalWithOptions(root, MarshalOptions{PreserveFormat: true})
+				if err != nil {
+					t.Fatalf("MarshalWithOptions() unexpected error: %s", err)
+				}
+				if string(result) != test.expected {
+					t.Errorf("MarshalWithOptions() wrong result:\n%s\n%s", result, test.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshalWithOptions_preserveFormatJSON5(t *testing.T) {
+	root := Must(UnmarshalJSON5([]byte(`{a: 1, /* b */ b: [1,]}`)))
+	if err := root.MustKey("a").SetNumeric(2); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	result, err := MarshalWithOptions(root, MarshalOptions{PreserveFormat: true})
+	if err != nil {
+		t.Fatalf("MarshalWithOptions() unexpected error: %s", err)
+	}
+	if string(result) != `{"a":2,"b":[1]}` {
+		t.Errorf("MarshalWithOptions() wrong result: %s", result)
+	}
+}
+
+func ExampleMarshalIndent() {
+	root := Must(Unmarshal([]byte(`{"name":"config","tags":["a","b"],"empty":{}}`)))
+	if err := root.AppendObject("version", NumericNode("", 2)); err != nil {
+		panic(err)
+	}
+	result, err := MarshalIndent(root, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(result))
+	// Output:
+	// {
+	//   "name": "config",
+	//   "tags": [
+	//     "a",
+	//     "b"
+	//   ],
+	//   "empty": {},
+	//   "version": 2
+	// }
+}
+
+func TestMarshalIndent(t *testing.T) {
+	input := []byte(`{"a":[1,{"b":null}],"c":"d"}`)
+	root := Must(Unmarshal(input))
+	result, err := MarshalIndent(root, "", "\t")
+	if err != nil {
+		t.Fatalf("MarshalIndent() unexpected error: %s", err)
+	}
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, input, "", "\t"); err != nil {
+		t.Fatalf("json.Indent() unexpected error: %s", err)
+	}
+	if string(result) != buf.String() {
+		t.Errorf("MarshalIndent() wrong result:\n%s\n%s", result, buf.String())
+	}
+	if result, err = MarshalIndent(Must(Unmarshal([]byte(` "a" `))), "", "\t"); err != nil || string(result) != `"a"` {
+		t.Errorf("MarshalIndent() wrong result of scalar: %s, %v", result, err)
+	}
+	if _, err = MarshalIndent(&Node{_type: Object}, "", "\t"); err == nil {
+		t.Errorf("MarshalIndent() expected error")
+	}
+}
+
+func mustBytes(result []byte, err error) []byte {
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package ajson
+
+import (
+	"io"
+)
+
+// encoderBufferSize is a size of the data, that Encoder keeps in memory before writing it
+const encoderBufferSize = 32 * 1024
+
+// Encoder writes JSON values to an output stream.
+type Encoder struct {
+	writer  io.Writer
+	options MarshalOptions
+	buffer  []byte
+}
+
+// NewEncoder returns a new encoder that writes to writer. It escapes HTML symbols by default, as Marshal does.
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{
+		writer:  writer,
+		options: MarshalOptions{EscapeHTML: true},
+	}
+}
+
+// SetOptions sets the options for the next values, see MarshalWithOptions.
+func (e *Encoder) SetOptions(options MarshalOptions) {
+	e.options = options
+}
+
+// Encode writes the JSON encoding of the node to the stream, followed by a newline character.
+//
+// Value is written by parts, so in case of an error the stream can contain a part of it.
+func (e *Encoder) Encode(node *Node) error {
+	m := &marshaler{
+		options: e.options,
+		result:  e.buffer[:0],
+		writer:  e.writer,
+	}
+	if err := m.marshal(node); err != nil {
+		return err
+	}
+	m.result = append(m.result, skipN)
+	err := m.flush()
+	e.buffer = m.result
+	return err
+}
@@ -0,0 +1,123 @@
+package ajson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleEncoder() {
+	encoder := NewEncoder(os.Stdout)
+	for _, data := range []string{`{"name": "first"}`, `[1, 2, 3]`} {
+		root := Must(Unmarshal([]byte(data)))
+		if err := encoder.Encode(root); err != nil {
+			panic(err)
+		}
+	}
+	encoder.SetOptions(MarshalOptions{SortKeys: true})
+	if err := encoder.Encode(ObjectNode("", map[string]*Node{"b": NullNode(""), "a": NumericNode("", 1)})); err != nil {
+		panic(err)
+	}
+	// Output:
+	// {"name": "first"}
+	// [1, 2, 3]
+	// {"a":1,"b":null}
+}
+
+// limitWriter fails on the write after the limit
+type limitWriter struct {
+	bytes.Buffer
+	limit   int
+	maxSize int
+}
+
+func (w *limitWriter) Write(data []byte) (int, error) {
+	if len(data) > w.maxSize {
+		w.maxSize = len(data)
+	}
+	if w.limit > 0 && w.Len()+len(data) > w.limit {
+		return 0, errors.New("limit")
+	}
+	return w.Buffer.Write(data)
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     *Node
+		options  MarshalOptions
+		expected string
+	}{
+		{name: "clean", node: Must(Unmarshal([]byte(`{"a": [1, 2]}`))), expected: "{\"a\": [1, 2]}\n"},
+		{name: "dirty", node: ArrayNode("", []*Node{StringNode("", "a<b"), BoolNode("", true)}), options: MarshalOptions{EscapeHTML: true}, expected: "[\"a\\u003cb\",true]\n"},
+		{name: "unescaped", node: ArrayNode("", []*Node{StringNode("", "a<b"), BoolNode("", true)}), expected: "[\"a<b\",true]\n"},
+		{name: "indent", node: Must(Unmarshal([]byte(`{"a": [1, 2]}`))), options: MarshalOptions{Indent: " "}, expected: "{\n \"a\":[\n  1,\n  2\n ]\n}\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder := NewEncoder(&buf)
+			encoder.SetOptions(test.options)
+			if err := encoder.Encode(test.node); err != nil {
+				t.Fatalf("Encode() unexpected error: %s", err)
+			}
+			if buf.String() != test.expected {
+				t.Errorf("Encode() wrong result: %q", buf.String())
+			}
+			expected := mustBytes(MarshalWithOptions(test.node, test.options))
+			if buf.String() != string(expected)+"\n" {
+				t.Errorf("Encode() differs from MarshalWithOptions(): %s", expected)
+			}
+		})
+	}
+}
+
+func TestEncoder_Encode_large(t *testing.T) {
+	root := ArrayNode("", nil)
+	for i := 0; i < 10000; i++ {
+		child := Must(Unmarshal([]byte(fmt.Sprintf(`{"id":%d,"name":"item"}`, i))))
+		if err := child.AppendObject("value", StringNode("", strings.Repeat("x", i%100))); err != nil {
+			t.Fatalf("AppendObject() unexpected error: %s", err)
+		}
+		if err := root.AppendArray(child); err != nil {
+			t.Fatalf("AppendArray() unexpected error: %s", err)
+		}
+	}
+	writer := &limitWriter{}
+	if err := NewEncoder(writer).Encode(root); err != nil {
+		t.Fatalf("Encode() unexpected error: %s", err)
+	}
+	if writer.maxSize > 2*encoderBufferSize {
+		t.Errorf("Encode() writes too big parts: %d", writer.maxSize)
+	}
+	if writer.String() != root.String()+"\n" {
+		t.Errorf("Encode() wrong result")
+	}
+
+	source := Must(Unmarshal([]byte(`[` + strings.Repeat(`"x",`, encoderBufferSize) + `"x"]`)))
+	writer = &limitWriter{}
+	if err := NewEncoder(writer).Encode(ArrayNode("", []*Node{NullNode(""), source})); err != nil {
+		t.Fatalf("Encode() unexpected error: %s", err)
+	}
+	if writer.String() != "[null,"+string(source.Source())+"]\n" {
+		t.Errorf("Encode() wrong result")
+	}
+}
+
+func TestEncoder_Encode_errors(t *testing.T) {
+	root := ArrayNode("", nil)
+	for i := 0; i < 10000; i++ {
+		if err := root.AppendArray(StringNode("", "value")); err != nil {
+			t.Fatalf("AppendArray() unexpected error: %s", err)
+		}
+	}
+	if err := NewEncoder(&limitWriter{limit: 100}).Encode(root); err == nil || err.Error() != "limit" {
+		t.Errorf("Encode() wrong error: %v", err)
+	}
+	if err := NewEncoder(&limitWriter{limit: 100}).Encode(&Node{_type: Object}); err == nil {
+		t.Errorf("Encode() expected error")
+	}
+}
@@ -1,6 +1,11 @@
 package ajson
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
 
 // Error is common struct to provide internal errors
 type Error struct {
@@ -9,8 +14,15 @@ type Error struct {
 	Char    byte
 	Message string
 	Value   interface{}
+	// Line and Column are the position of the Index in the source (both starts from 1), zero if unknown.
+	Line   int
+	Column int
+	// Snippet is an excerpt of the source line with a caret under the Index.
+	Snippet string
 }
 
+const snippetRadius = 32
+
 // ErrorType is container for reflection type of error
 type ErrorType int
 
@@ -27,6 +39,12 @@ const (
 	Unparsed
 	// UnsupportedType means that wrong type was given
 	UnsupportedType
+	// DuplicateKey means that object has a repeated key
+	DuplicateKey
+	// LimitExceeded means that data exceeds the given restrictions
+	LimitExceeded
+	// TypeMismatch means that value of the node can't be decoded into the Go value
+	TypeMismatch
 )
 
 func errorSymbol(b *buffer) error {
@@ -38,7 +56,7 @@ func errorSymbol(b *buffer) error {
 		Type:  WrongSymbol,
 		Index: b.index,
 		Char:  symbol,
-	}
+	}.withPosition(b.data)
 }
 
 func errorAt(index int, symbol byte) error {
@@ -49,10 +67,46 @@ func errorAt(index int, symbol byte) error {
 	}
 }
 
+// errorIn returns an error of the given type, positioned at the index of the data
+func errorIn(data []byte, _type ErrorType, index int) error {
+	err := Error{
+		Type:  _type,
+		Index: index,
+	}
+	if _type == WrongSymbol && index < len(data) {
+		err.Char = data[index]
+	}
+	return err.withPosition(data)
+}
+
 func errorEOF(b *buffer) error {
 	return Error{
 		Type:  UnexpectedEOF,
 		Index: b.index,
+	}.withPosition(b.data)
+}
+
+func errorDuplicate(index int, key string) error {
+	return Error{
+		Type:    DuplicateKey,
+		Index:   index,
+		Message: key,
+	}
+}
+
+func errorLimit(index int, format string, args ...interface{}) error {
+	return Error{
+		Type:    LimitExceeded,
+		Index:   index,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func errorMismatch(path string, message string) error {
+	return Error{
+		Type:    TypeMismatch,
+		Message: message,
+		Value:   path,
 	}
 }
 
@@ -82,13 +136,67 @@ func errorRequest(format string, args ...interface{}) error {
 	}
 }
 
+// withPosition fills Line, Column and Snippet of the error by its Index in the source data
+func (err Error) withPosition(data []byte) Error {
+	index := err.Index
+	if index > len(data) {
+		index = len(data)
+	}
+	err.Line, err.Column = location(data, index)
+	start := lineStart(data, index)
+
+	end := index
+	for end < len(data) && data[end] != skipN && data[end] != skipR {
+		end++
+	}
+	var prefix, suffix string
+	if index-start > snippetRadius {
+		start = index - snippetRadius
+		for start < index && !utf8.RuneStart(data[start]) {
+			start++
+		}
+		prefix = "..."
+	}
+	if end-index > snippetRadius {
+		end = index + snippetRadius
+		for end < len(data) && !utf8.RuneStart(data[end]) {
+			end++
+		}
+		suffix = "..."
+	}
+	line := strings.ReplaceAll(string(data[start:end]), "\t", " ")
+	caret := strings.Repeat(" ", len(prefix)+utf8.RuneCount(data[start:index])) + "^"
+	err.Snippet = prefix + line + suffix + "\n" + caret
+	return err
+}
+
+// location returns line and column (both starts from 1) of the index in the data
+func location(data []byte, index int) (line, column int) {
+	line = 1 + bytes.Count(data[:index], []byte{skipN})
+	column = utf8.RuneCount(data[lineStart(data, index):index]) + 1
+	return
+}
+
+// lineStart returns the index of the first symbol of the line with the given index
+func lineStart(data []byte, index int) int {
+	return bytes.LastIndexByte(data[:index], skipN) + 1
+}
+
+// position returns the human-readable position of the error, if it's known
+func (err Error) position() string {
+	if err.Line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (line %d, column %d)", err.Line, err.Column)
+}
+
 // Error interface implementation
 func (err Error) Error() string {
 	switch err.Type {
 	case WrongSymbol:
-		return fmt.Sprintf("wrong symbol '%s' at %d", []byte{err.Char}, err.Index)
+		return fmt.Sprintf("wrong symbol '%s' at %d", []byte{err.Char}, err.Index) + err.position()
 	case UnexpectedEOF:
-		return "unexpected end of file"
+		return "unexpected end of file" + err.position()
 	case WrongType:
 		return "wrong type of Node"
 	case UnsupportedType:
@@ -97,6 +205,12 @@ func (err Error) Error() string {
 		return "not parsed yet"
 	case WrongRequest:
 		return fmt.Sprintf("wrong request: %s", err.Message)
+	case DuplicateKey:
+		return fmt.Sprintf("duplicate key '%s' at %d", err.Message, err.Index)
+	case LimitExceeded:
+		return fmt.Sprintf("limit exceeded: %s at %d", err.Message, err.Index)
+	case TypeMismatch:
+		return fmt.Sprintf("type mismatch: %s at %s", err.Message, err.Value)
 	}
 	return fmt.Sprintf("unknown error: '%s' at %d", []byte{err.Char}, err.Index)
 }
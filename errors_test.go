@@ -1,6 +1,9 @@
 package ajson
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestError_Error(t *testing.T) {
 	tests := []struct {
@@ -12,6 +15,8 @@ func TestError_Error(t *testing.T) {
 		{name: "UnexpectedEOF", _type: UnexpectedEOF, message: "unexpected end of file"},
 		{name: "WrongType", _type: WrongType, message: "wrong type of Node"},
 		{name: "WrongRequest", _type: WrongRequest, message: "wrong request: example error"},
+		{name: "DuplicateKey", _type: DuplicateKey, message: "duplicate key 'example error' at 10"},
+		{name: "LimitExceeded", _type: LimitExceeded, message: "limit exceeded: example error at 10"},
 		{name: "unknown", _type: -666, message: "unknown error: 'S' at 10"},
 	}
 	for _, test := range tests {
@@ -83,3 +88,63 @@ func Test_unsupportedType(t *testing.T) {
 		})
 	}
 }
+
+func TestError_withPosition(t *testing.T) {
+	long := strings.Repeat("0123456789", 5)
+	tests := []struct {
+		name    string
+		data    string
+		index   int
+		line    int
+		column  int
+		snippet string
+	}{
+		{name: "first symbol", data: `x`, index: 0, line: 1, column: 1, snippet: "x\n^"},
+		{name: "first line", data: `{"a": x}`, index: 6, line: 1, column: 7, snippet: "{\"a\": x}\n      ^"},
+		{name: "third line", data: "{\n\t\"a\": 1,\n\t\"b\": x\n}", index: 17, line: 3, column: 7, snippet: " \"b\": x\n      ^"},
+		{name: "CRLF", data: "[\r\n1,\r\n]", index: 7, line: 3, column: 1, snippet: "]\n^"},
+		{name: "EOF", data: "[1,\n", index: 4, line: 2, column: 1, snippet: "\n^"},
+		{name: "UTF-8", data: `["ключ", x]`, index: 13, line: 1, column: 10, snippet: "[\"ключ\", x]\n         ^"},
+		{
+			name:    "long line",
+			data:    "[" + long + "x" + long + "]",
+			index:   51,
+			line:    1,
+			column:  52,
+			snippet: "..." + long[18:] + "x" + long[:31] + "...\n" + strings.Repeat(" ", 35) + "^",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Error{Type: WrongSymbol, Index: test.index}.withPosition([]byte(test.data))
+			if err.Line != test.line || err.Column != test.column {
+				t.Errorf("withPosition() wrong position: %d:%d", err.Line, err.Column)
+			}
+			if err.Snippet != test.snippet {
+				t.Errorf("withPosition() wrong snippet:\n%s\nexpected:\n%s", err.Snippet, test.snippet)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_errorPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+	}{
+		{name: "WrongSymbol", data: "{\n  \"a\": [1, 2,, 3]\n}", message: "wrong symbol ',' at 15 (line 2, column 14)"},
+		{name: "UnexpectedEOF", data: "{\n  \"a\": [1, 2", message: "unexpected end of file (line 2, column 12)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(test.data))
+			if err == nil {
+				t.Fatalf("Unmarshal() error expected")
+			}
+			if err.Error() != test.message {
+				t.Errorf("Unmarshal() wrong error: %s", err)
+			}
+		})
+	}
+}
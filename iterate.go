@@ -0,0 +1,39 @@
+package ajson
+
+import (
+	"strconv"
+)
+
+// EachChild calls the callback for each child of the current node until the callback returns false: in the index
+// order for an Array, and in the same order as Keys returns them for an Object (the order of the source document,
+// then the appended keys). The key is empty for the elements of an Array, the index is -1 for the members of an
+// Object.
+//
+// Unlike Inheritors, it doesn't allocate the slice of children and doesn't sort the keys. The node should not be
+// changed during the iteration.
+func (n *Node) EachChild(callback func(key string, index int, child *Node) bool) {
+	if n != nil {
+		n.eachChild(callback)
+	}
+}
+
+// eachChild calls the callback for each child of the container, and returns false if the iteration was stopped
+func (n *Node) eachChild(callback func(key string, index int, child *Node) bool) bool {
+	n.load()
+	switch n.Type() {
+	case Array:
+		var buf [20]byte
+		for i := 0; i < len(n.children); i++ {
+			if child, ok := n.children[string(strconv.AppendInt(buf[:0], int64(i), 10))]; ok && !callback("", i, child) {
+				return false
+			}
+		}
+	case Object:
+		for _, key := range n.keys {
+			if child, ok := n.children[key]; ok && !callback(key, -1, child) {
+				return false
+			}
+		}
+	}
+	return true
+}
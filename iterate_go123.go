@@ -0,0 +1,45 @@
+//go:build go1.23
+// +build go1.23
+
+package ajson
+
+import (
+	"iter"
+)
+
+// Children returns the iterator over the children of the current node, in the same order as EachChild, e.g.:
+//
+//	for child := range node.Children() {
+//		fmt.Println(child.Path())
+//	}
+func (n *Node) Children() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		n.EachChild(func(_ string, _ int, child *Node) bool {
+			return yield(child)
+		})
+	}
+}
+
+// Elements returns the iterator over the indexes and the elements of an Array, in the index order. It's empty for
+// other types.
+func (n *Node) Elements() iter.Seq2[int, *Node] {
+	return func(yield func(int, *Node) bool) {
+		if n.IsArray() {
+			n.EachChild(func(_ string, index int, child *Node) bool {
+				return yield(index, child)
+			})
+		}
+	}
+}
+
+// Members returns the iterator over the keys and the members of an Object, in the same order as Keys. It's empty for
+// other types.
+func (n *Node) Members() iter.Seq2[string, *Node] {
+	return func(yield func(string, *Node) bool) {
+		if n.IsObject() {
+			n.EachChild(func(key string, _ int, child *Node) bool {
+				return yield(key, child)
+			})
+		}
+	}
+}
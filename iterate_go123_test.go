@@ -0,0 +1,80 @@
+//go:build go1.23
+// +build go1.23
+
+package ajson
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleNode_Members() {
+	root := Must(Unmarshal([]byte(`{"price": 8.95, "author": "Nigel Rees", "tags": ["reference", "sayings"]}`)))
+	for key, child := range root.Members() {
+		fmt.Println(key, child)
+	}
+	for index, child := range root.MustKey("tags").Elements() {
+		fmt.Println(index, child)
+	}
+	// Output:
+	// price 8.95
+	// author "Nigel Rees"
+	// tags ["reference", "sayings"]
+	// 0 "reference"
+	// 1 "sayings"
+}
+
+func TestNode_Children(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"b": [1, 2, 3], "a": {"c": true}}`)))
+	var result []*Node
+	for child := range root.Children() {
+		result = append(result, child)
+	}
+	if !reflect.DeepEqual(result, []*Node{root.MustKey("b"), root.MustKey("a")}) {
+		t.Errorf("Children() wrong result: %v", result)
+	}
+	for child := range root.MustKey("b").Children() {
+		if child.MustNumeric() != 1 {
+			t.Errorf("Children() wrong result: %s", child)
+		}
+		break
+	}
+	for child := range root.MustKey("a").MustKey("c").Children() {
+		t.Errorf("Children() wrong result: %s", child)
+	}
+}
+
+func TestNode_Elements(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"b": [1, 2, 3], "a": {"c": true}}`)))
+	var result []string
+	for index, child := range root.MustKey("b").Elements() {
+		result = append(result, fmt.Sprintf("%d=%s", index, child))
+		if index == 1 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(result, []string{"0=1", "1=2"}) {
+		t.Errorf("Elements() wrong result: %v", result)
+	}
+	for index := range root.Elements() {
+		t.Errorf("Elements() wrong result: %d", index)
+	}
+}
+
+func TestNode_Members(t *testing.T) {
+	root := Must(UnmarshalWithOptions([]byte(`{"b": [1, 2, 3], "a": {"c": true}, "d": null}`), Options{Lazy: true}))
+	var result []string
+	for key, child := range root.Members() {
+		result = append(result, fmt.Sprintf("%s=%s", key, child))
+		if key == "a" {
+			break
+		}
+	}
+	if !reflect.DeepEqual(result, []string{"b=[1, 2, 3]", `a={"c": true}`}) {
+		t.Errorf("Members() wrong result: %v", result)
+	}
+	for key := range root.MustKey("b").Members() {
+		t.Errorf("Members() wrong result: %s", key)
+	}
+}
@@ -0,0 +1,100 @@
+package ajson
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleNode_EachChild() {
+	root := Must(Unmarshal([]byte(`{"price": 8.95, "author": "Nigel Rees", "tags": ["reference", "sayings"]}`)))
+	root.EachChild(func(key string, _ int, child *Node) bool {
+		fmt.Println(key, child)
+		return true
+	})
+	root.MustKey("tags").EachChild(func(_ string, index int, child *Node) bool {
+		fmt.Println(index, child)
+		return false
+	})
+	// Output:
+	// price 8.95
+	// author "Nigel Rees"
+	// tags ["reference", "sayings"]
+	// 0 "reference"
+}
+
+func TestNode_EachChild(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		limit    int
+		expected []string
+	}{
+		{name: "array", data: `[0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11]`, limit: -1, expected: []string{
+			":0=0", ":1=1", ":2=2", ":3=3", ":4=4", ":5=5", ":6=6", ":7=7", ":8=8", ":9=9", ":10=10", ":11=11",
+		}},
+		{name: "array stop", data: `[0, 1, 2]`, limit: 2, expected: []string{":0=0", ":1=1"}},
+		{name: "object", data: `{"c": 1, "a": [2], "b": {}}`, limit: -1, expected: []string{"c:-1=1", "a:-1=[2]", "b:-1={}"}},
+		{name: "object stop", data: `{"c": 1, "a": [2], "b": {}}`, limit: 1, expected: []string{"c:-1=1"}},
+		{name: "empty", data: `[]`, limit: -1, expected: nil},
+		{name: "scalar", data: `"a"`, limit: -1, expected: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				root := Must(UnmarshalWithOptions([]byte(test.data), Options{Lazy: lazy}))
+				var result []string
+				root.EachChild(func(key string, index int, child *Node) bool {
+					result = append(result, fmt.Sprintf("%s:%d=%s", key, index, child))
+					return len(result) != test.limit
+				})
+				if !reflect.DeepEqual(result, test.expected) {
+					t.Errorf("EachChild() wrong result: %v", result)
+				}
+			}
+		})
+	}
+	(*Node)(nil).EachChild(func(string, int, *Node) bool {
+		t.Errorf("EachChild() called for nil node")
+		return true
+	})
+}
+
+func TestNode_EachChild_keys(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"z": 1, "y": 2, "x": 3, "a": {"b": 4}, "": 5}`)))
+	if err := root.AppendObject("m", NumericNode("", 6)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.DeleteKey("y"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	var result []string
+	root.EachChild(func(key string, _ int, child *Node) bool {
+		if child.Key() != key {
+			t.Errorf("EachChild() wrong child for %q: %s", key, child.Path())
+		}
+		result = append(result, key)
+		return true
+	})
+	if !reflect.DeepEqual(result, root.Keys()) {
+		t.Errorf("EachChild() wrong order: %v", result)
+	}
+}
+
+func TestNode_EachChild_allocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not checked with the race detector")
+	}
+	for _, data := range []string{`[1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30]`, `{"c": 1, "b": 2, "a": 3}`} {
+		root := Must(Unmarshal([]byte(data)))
+		count := 0
+		callback := func(string, int, *Node) bool {
+			count++
+			return true
+		}
+		root.EachChild(callback)
+		if allocations := testing.AllocsPerRun(100, func() { root.EachChild(callback) }); allocations > 0 {
+			t.Errorf("EachChild() wrong allocations for %s: %v", data, allocations)
+		}
+	}
+}
@@ -0,0 +1,558 @@
+package ajson
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	_infinity = []byte("Infinity")
+	_nan      = []byte("NaN")
+)
+
+// json5MaxDepth is the default nesting limit of the containers for JSON5 parser: it's recursive, so the deep nesting
+// would overflow the stack.
+const json5MaxDepth = 10000
+
+// UnmarshalJSON5 parses the JSON5 (or JSONC) data and return the root node of struct.
+//
+// In addition to JSON it accepts: single-line and multi-line comments, trailing commas, single-quoted strings,
+// unquoted keys, hexadecimal numbers, leading and trailing decimal points, explicit plus sign, Infinity and NaN.
+//
+// Nodes keep the link to the original data, so Source of the untouched nodes returns JSON5 as is, but Marshal
+// returns valid JSON: values and containers, written with JSON5 syntax, are encoded again. NaN and Infinity can't be
+// represented in JSON, so Marshal returns an error for them.
+func UnmarshalJSON5(data []byte) (root *Node, err error) {
+	return UnmarshalWithOptions(data, Options{JSON5: true})
+}
+
+// json5 is a recursive descent parser for the lenient JSON5 syntax
+type json5 struct {
+	buf     *buffer
+	options Options
+	depth   int
+	// lenient is true, if the current container contains JSON5 syntax
+	lenient bool
+	// comma is true, if the last element of the current container was followed by comma
+	comma bool
+}
+
+func unmarshalJSON5(data []byte, options Options) (root *Node, err error) {
+	if options.MaxDepth <= 0 {
+		options.MaxDepth = json5MaxDepth
+	}
+	p := &json5{
+		buf:     newBuffer(data),
+		options: options,
+	}
+	if err = p.skip(); err != nil {
+		return nil, err
+	}
+	if root, err = p.value(nil, nil); err != nil {
+		return nil, err
+	}
+	if err = p.skip(); err != nil {
+		return nil, err
+	}
+	if p.buf.index < p.buf.length {
+		return nil, p.buf.errorSymbol()
+	}
+	return root, nil
+}
+
+// skip moves buffer over whitespaces and comments
+func (p *json5) skip() error {
+	b := p.buf
+	for b.index < b.length {
+		c := b.data[b.index]
+		switch {
+		case c == skipS || c == skipN || c == skipR || c == skipT:
+			b.index++
+		case c == '\v' || c == '\f':
+			p.lenient = true
+			b.index++
+		case c == slash && b.index+1 < b.length && b.data[b.index+1] == slash:
+			p.lenient = true
+			for b.index < b.length && b.data[b.index] != skipN && b.data[b.index] != skipR {
+				b.index++
+			}
+		case c == slash && b.index+1 < b.length && b.data[b.index+1] == asterisk:
+			p.lenient = true
+			end := bytes.Index(b.data[b.index+2:], []byte("*/"))
+			if end == -1 {
+				b.index = b.length
+				return b.errorEOF()
+			}
+			b.index += end + 4
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(b.data[b.index:])
+			if r != '\uFEFF' && r != '\u2028' && r != '\u2029' && !unicode.Is(unicode.Zs, r) {
+				return nil
+			}
+			p.lenient = true
+			b.index += size
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *json5) value(parent *Node, key *string) (node *Node, err error) {
+	b := p.buf
+	if b.index >= b.length {
+		return nil, b.errorEOF()
+	}
+	switch b.data[b.index] {
+	case bracesL:
+		return p.object(parent, key)
+	case bracketL:
+		return p.array(parent, key)
+	case quotes, quote:
+		if node, err = newNode(parent, b, String, &key); err != nil {
+			return nil, err
+		}
+		var value string
+		if value, err = p.string(); err != nil {
+			return nil, err
+		}
+		node.value.Store(value)
+	case 't':
+		if node, err = newNode(parent, b, Bool, &key); err != nil {
+			return nil, err
+		}
+		err = p.word(_true)
+	case 'f':
+		if node, err = newNode(parent, b, Bool, &key); err != nil {
+			return nil, err
+		}
+		err = p.word(_false)
+	case 'n':
+		if node, err = newNode(parent, b, Null, &key); err != nil {
+			return nil, err
+		}
+		err = p.word(_null)
+	default:
+		if node, err = newNode(parent, b, Numeric, &key); err != nil {
+			return nil, err
+		}
+		var value float64
+		if value, err = p.numeric(); err != nil {
+			return nil, err
+		}
+		node.value.Store(value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	node.borders[1] = b.index
+	if (node._type == String || node._type == Numeric) && !Valid(node.Source()) {
+		node.lenient = true
+		p.lenient = true
+	}
+	return node, nil
+}
+
+func (p *json5) open(parent *Node, key *string, _type NodeType) (node *Node, err error) {
+	p.depth++
+	if p.options.MaxDepth > 0 && p.depth > p.options.MaxDepth {
+		return nil, errorLimit(p.buf.index, "max depth %d", p.options.MaxDepth)
+	}
+	if node, err = newNode(parent, p.buf, _type, &key); err != nil {
+		return nil, err
+	}
+	p.buf.index++
+	p.comma = false
+	return node, nil
+}
+
+// close finishes the container, which is lenient if it contains JSON5 syntax; outer is the lenient flag of the
+// parent container
+func (p *json5) close(node *Node, outer bool) *Node {
+	p.depth--
+	p.buf.index++
+	node.borders[1] = p.buf.index
+	node.lenient = p.lenient || p.comma
+	p.lenient = outer || node.lenient
+	return node
+}
+
+func (p *json5) object(parent *Node, key *string) (node *Node, err error) {
+	b := p.buf
+	outer := p.lenient
+	p.lenient = false
+	if node, err = p.open(parent, key, Object); err != nil {
+		return nil, err
+	}
+	for {
+		if err = p.skip(); err != nil {
+			return nil, err
+		}
+		if b.index >= b.length {
+			return nil, b.errorEOF()
+		}
+		if b.data[b.index] == bracesR {
+			return p.close(node, outer), nil
+		}
+
+		start := b.index
+		var name string
+		if name, err = p.key(); err != nil {
+			return nil, err
+		}
+		if !Valid(b.data[start:b.index]) {
+			p.lenient = true
+		}
+		if err = p.skip(); err != nil {
+			return nil, err
+		}
+		if b.index >= b.length {
+			return nil, b.errorEOF()
+		}
+		if b.data[b.index] != colon {
+			return nil, b.errorSymbol()
+		}
+		b.index++
+		if err = p.skip(); err != nil {
+			return nil, err
+		}
+
+		shadowed := node.children[name]
+		if shadowed != nil && p.options.DuplicateKeys == DuplicateKeyError {
+			return nil, errorDuplicate(start, name)
+		}
+		if _, err = p.value(node, &name); err != nil {
+			return nil, err
+		}
+		if shadowed != nil && p.options.DuplicateKeys == DuplicateKeyFirstWins {
+			node.children[name] = shadowed
+		}
+
+		if err = p.next(bracesR); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *json5) array(parent *Node, key *string) (node *Node, err error) {
+	b := p.buf
+	outer := p.lenient
+	p.lenient = false
+	if node, err = p.open(parent, key, Array); err != nil {
+		return nil, err
+	}
+	for {
+		if err = p.skip(); err != nil {
+			return nil, err
+		}
+		if b.index >= b.length {
+			return nil, b.errorEOF()
+		}
+		if b.data[b.index] == bracketR {
+			return p.close(node, outer), nil
+		}
+		if _, err = p.value(node, nil); err != nil {
+			return nil, err
+		}
+		if err = p.next(bracketR); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// next moves buffer after the value of container: to the next element or to the closing bracket
+func (p *json5) next(closing byte) (err error) {
+	b := p.buf
+	if err = p.skip(); err != nil {
+		return err
+	}
+	if b.index >= b.length {
+		return b.errorEOF()
+	}
+	switch b.data[b.index] {
+	case coma:
+		p.comma = true
+		b.index++
+	case closing:
+		p.comma = false
+	default:
+		return b.errorSymbol()
+	}
+	return nil
+}
+
+// key reads the quoted or the identifier name of the object element
+func (p *json5) key() (string, error) {
+	b := p.buf
+	c := b.data[b.index]
+	if c == quotes || c == quote {
+		return p.string()
+	}
+	start := b.index
+	for b.index < b.length {
+		r, size := utf8.DecodeRune(b.data[b.index:])
+		if !isIdentifier(r, b.index == start) {
+			break
+		}
+		b.index += size
+	}
+	if start == b.index {
+		return "", b.errorSymbol()
+	}
+	return string(b.data[start:b.index]), nil
+}
+
+// string reads the quoted string and returns its value
+func (p *json5) string() (string, error) {
+	b := p.buf
+	start := b.index
+	border := b.data[b.index]
+	for b.index++; b.index < b.length; b.index++ {
+		switch b.data[b.index] {
+		case border:
+			b.index++
+			value, ok := unquoteJSON5(b.data[start+1 : b.index-1])
+			if !ok {
+				return "", errorIn(b.data, WrongSymbol, start)
+			}
+			return value, nil
+		case backslash:
+			b.index++
+			if b.index+1 < b.length && b.data[b.index] == skipR && b.data[b.index+1] == skipN {
+				b.index++
+			}
+		case skipN, skipR:
+			return "", b.errorSymbol()
+		}
+	}
+	return "", b.errorEOF()
+}
+
+func (p *json5) word(word []byte) error {
+	b := p.buf
+	if !bytes.HasPrefix(b.data[b.index:], word) {
+		return p.unexpected(len(word))
+	}
+	b.index += len(word)
+	return p.delimiter()
+}
+
+// json5Number returns JSON representation of the number, written with JSON5 syntax: integers keep all the digits
+func json5Number(node *Node) (string, error) {
+	if integer, err := node.GetBigInt(); err == nil {
+		return integer.String(), nil
+	}
+	value, err := node.GetNumeric()
+	if err != nil {
+		return "", err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", errorRequest("unsupported number %v", value)
+	}
+	return string(formatFloat(value)), nil
+}
+
+// numeric reads the decimal or hexadecimal number, Infinity or NaN, with an optional sign
+func (p *json5) numeric() (value float64, err error) {
+	b := p.buf
+	start := b.index
+	sign := 1.0
+	if c := b.data[b.index]; c == plus || c == minus {
+		if c == minus {
+			sign = -1
+		}
+		b.index++
+	}
+	rest := b.data[b.index:]
+	switch {
+	case bytes.HasPrefix(rest, _infinity):
+		b.index += len(_infinity)
+		value = math.Inf(int(sign))
+	case bytes.HasPrefix(rest, _nan):
+		b.index += len(_nan)
+		value = math.NaN()
+	case bytes.HasPrefix(rest, []byte("0x")) || bytes.HasPrefix(rest, []byte("0X")):
+		b.index += 2
+		digits := b.index
+		for ; b.index < b.length; b.index++ {
+			digit := hexDigit(b.data[b.index])
+			if digit < 0 {
+				break
+			}
+			value = value*16 + float64(digit)
+		}
+		if digits == b.index {
+			return 0, p.unexpected(0)
+		}
+		value *= sign
+	default:
+		digits := 0
+		if b.index+1 < b.length && b.data[b.index] == '0' && isDigit(b.data[b.index+1]) {
+			b.index++
+			return 0, b.errorSymbol()
+		}
+		for ; b.index < b.length && isDigit(b.data[b.index]); b.index++ {
+			digits++
+		}
+		if b.index < b.length && b.data[b.index] == dot {
+			for b.index++; b.index < b.length && isDigit(b.data[b.index]); b.index++ {
+				digits++
+			}
+		}
+		if digits == 0 {
+			return 0, p.unexpected(0)
+		}
+		if b.index < b.length && (b.data[b.index] == 'e' || b.data[b.index] == 'E') {
+			b.index++
+			if b.index < b.length && (b.data[b.index] == plus || b.data[b.index] == minus) {
+				b.index++
+			}
+			digits = b.index
+			for ; b.index < b.length && isDigit(b.data[b.index]); b.index++ {
+			}
+			if digits == b.index {
+				return 0, p.unexpected(0)
+			}
+		}
+		value, err = strconv.ParseFloat(string(b.data[start:b.index]), 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return 0, errorIn(b.data, WrongSymbol, start)
+		}
+	}
+	return value, p.delimiter()
+}
+
+// delimiter checks that the scalar value is not followed by identifier symbols
+func (p *json5) delimiter() error {
+	b := p.buf
+	if b.index < b.length {
+		if r, _ := utf8.DecodeRune(b.data[b.index:]); isIdentifier(r, false) {
+			return b.errorSymbol()
+		}
+	}
+	return nil
+}
+
+// unexpected returns EOF error if the data ends within the next size bytes, or the wrong symbol error.
+func (p *json5) unexpected(size int) error {
+	b := p.buf
+	if b.index+size > b.length {
+		b.index = b.length
+		return b.errorEOF()
+	}
+	if b.index >= b.length {
+		return b.errorEOF()
+	}
+	return b.errorSymbol()
+}
+
+// isIdentifier returns true if the rune can be used in the ECMAScript identifier name
+func isIdentifier(r rune, first bool) bool {
+	if r == '$' || r == '_' || unicode.IsLetter(r) {
+		return true
+	}
+	if first {
+		return false
+	}
+	return unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) ||
+		unicode.Is(unicode.Pc, r) || r == '\u200C' || r == '\u200D'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// hexDigit returns the value of the hexadecimal digit, or -1
+func hexDigit(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c - 'a' + 10)
+	case 'A' <= c && c <= 'F':
+		return int(c - 'A' + 10)
+	}
+	return -1
+}
+
+// unquoteJSON5 converts the content of the JSON5 string literal (without quotes) into an actual string.
+func unquoteJSON5(s []byte) (string, bool) {
+	if bytes.IndexByte(s, backslash) == -1 {
+		return string(s), true
+	}
+	var result strings.Builder
+	result.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != backslash {
+			result.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", false
+		}
+		switch c := s[i]; c {
+		case 'b':
+			result.WriteByte('\b')
+		case 'f':
+			result.WriteByte('\f')
+		case 'n':
+			result.WriteByte('\n')
+		case 'r':
+			result.WriteByte('\r')
+		case 't':
+			result.WriteByte('\t')
+		case 'v':
+			result.WriteByte('\v')
+		case '0':
+			if i+1 < len(s) && isDigit(s[i+1]) {
+				return "", false
+			}
+			result.WriteByte(0)
+		case 'x':
+			if i+2 >= len(s) || hexDigit(s[i+1]) < 0 || hexDigit(s[i+2]) < 0 {
+				return "", false
+			}
+			result.WriteRune(rune(hexDigit(s[i+1])*16 + hexDigit(s[i+2])))
+			i += 2
+		case 'u':
+			r := getu4(s[i-1:])
+			if r < 0 {
+				return "", false
+			}
+			i += 4
+			if utf16.IsSurrogate(r) {
+				if r2 := getu4(s[i+1:]); r2 >= 0 {
+					if dec := utf16.DecodeRune(r, r2); dec != unicode.ReplacementChar {
+						r = dec
+						i += 6
+					}
+				}
+			}
+			result.WriteRune(r)
+		case skipR:
+			// line continuation: \ CR LF
+			if i+1 < len(s) && s[i+1] == skipN {
+				i++
+			}
+		case skipN:
+			// line continuation: \ LF
+		default:
+			if c >= '1' && c <= '9' {
+				return "", false
+			}
+			r, size := utf8.DecodeRune(s[i:])
+			if r != '\u2028' && r != '\u2029' {
+				result.WriteRune(r)
+			}
+			i += size - 1
+		}
+	}
+	return result.String(), true
+}
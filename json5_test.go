@@ -0,0 +1,239 @@
+package ajson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ExampleUnmarshalJSON5() {
+	data := []byte(`{
+	// database settings
+	database: {
+		host: 'localhost',
+		port: 0x1538, /* 5432 */
+	},
+	ratio: .5,
+	limits: [+1, Infinity,],
+}`)
+	root, err := UnmarshalJSON5(data)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(root.MustKey("database").MustKey("host").MustString())
+	fmt.Println(root.MustKey("database").MustKey("port").MustNumeric())
+	fmt.Println(root.MustKey("ratio").MustNumeric())
+	fmt.Println(root.MustKey("limits").MustIndex(1).MustNumeric())
+	// Output:
+	// localhost
+	// 5432
+	// 0.5
+	// +Inf
+}
+
+func TestUnmarshalJSON5(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{name: "strict JSON", input: `{"a":[1,-2.5e3,"b\n",true,false,null,{}]}`, want: map[string]interface{}{"a": []interface{}{1.0, -2500.0, "b\n", true, false, nil, map[string]interface{}{}}}},
+		{name: "line comment", input: "// first\n[1, // second\n2]// last", want: []interface{}{1.0, 2.0}},
+		{name: "block comment", input: "/* a */[/* b */1/* c */,/** d **/2]/* e */", want: []interface{}{1.0, 2.0}},
+		{name: "trailing comma in array", input: `[1,2,]`, want: []interface{}{1.0, 2.0}},
+		{name: "trailing comma in object", input: `{"a":1,}`, want: map[string]interface{}{"a": 1.0}},
+		{name: "single quoted", input: `['a"b', 'c\'d']`, want: []interface{}{`a"b`, "c'd"}},
+		{name: "unquoted keys", input: `{a: 1, $b_2: 2, ключ: 3, 'c': 4}`, want: map[string]interface{}{"a": 1.0, "$b_2": 2.0, "ключ": 3.0, "c": 4.0}},
+		{name: "hexadecimal", input: `[0x1F, -0XfF, +0x0]`, want: []interface{}{31.0, -255.0, 0.0}},
+		{name: "decimal points", input: `[.5, 5., -.5e1, +1]`, want: []interface{}{0.5, 5.0, -5.0, 1.0}},
+		{name: "infinity", input: `[Infinity, -Infinity, +Infinity]`, want: []interface{}{math.Inf(1), math.Inf(-1), math.Inf(1)}},
+		{name: "overflow", input: `[1e400, -1e400, 1e-400]`, want: []interface{}{math.Inf(1), math.Inf(-1), 0.0}},
+		{name: "escapes", input: `'\x41B\0\v\q\
+end'`, want: "AB\x00\vqend"},
+		{name: "surrogate pair", input: `"\ud83d\ude00"`, want: "😀"},
+		{name: "whitespaces", input: "\ufeff\v\f\u00a0[ 1 ]\u2028", want: []interface{}{1.0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := UnmarshalJSON5([]byte(test.input))
+			if err != nil {
+				t.Fatalf("UnmarshalJSON5() unexpected error: %s", err)
+			}
+			got, err := root.Unpack()
+			if err != nil {
+				t.Fatalf("Unpack() unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("UnmarshalJSON5() got = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON5_NaN(t *testing.T) {
+	root, err := UnmarshalJSON5([]byte(`[NaN, -NaN]`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSON5() unexpected error: %s", err)
+	}
+	for _, node := range root.MustArray() {
+		if !math.IsNaN(node.MustNumeric()) {
+			t.Errorf("UnmarshalJSON5() NaN expected, got: %s", node)
+		}
+	}
+}
+
+func TestUnmarshalJSON5_source(t *testing.T) {
+	data := []byte(`{a: 'b', /* c */ d: [0x10,],}`)
+	root := Must(UnmarshalJSON5(data))
+	if string(root.Source()) != string(data) {
+		t.Errorf("Source() wrong value: %s", root.Source())
+	}
+	if string(root.MustKey("a").Source()) != `'b'` {
+		t.Errorf("Source() wrong value: %s", root.MustKey("a").Source())
+	}
+	if string(root.MustKey("d").MustIndex(0).Source()) != `0x10` {
+		t.Errorf("Source() wrong value: %s", root.MustKey("d").MustIndex(0).Source())
+	}
+	if line, column := root.MustKey("d").Position(); line != 1 || column != 21 {
+		t.Errorf("Position() wrong value: %d:%d", line, column)
+	}
+	if err := root.MustKey("a").SetString("e"); err != nil {
+		t.Fatalf("SetString() unexpected error: %s", err)
+	}
+	if root.String() != `{"a":"e","d":[16]}` {
+		t.Errorf("String() wrong value: %s", root)
+	}
+	if result := Must(Unmarshal([]byte(root.String()))); result.MustKey("a").MustString() != "e" {
+		t.Errorf("String() wrong value: %s", root)
+	}
+}
+
+func TestUnmarshalJSON5_marshal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: `{"a": [1, 2.5e1, "b"], "c": {"d": null}}`, expected: `{"a": [1, 2.5e1, "b"], "c": {"d": null}}`},
+		{input: `{"a": [1, 2], "b": {c: 3}}`, expected: `{"a":[1, 2],"b":{"c":3}}`},
+		{input: `{"a": [1, 2], "b": [3,]}`, expected: `{"a":[1, 2],"b":[3]}`},
+		{input: `{"a": [1, 2], "b": [3 /* c */]}`, expected: `{"a":[1, 2],"b":[3]}`},
+		{input: `{"a": [1, 2], "b": ['c\x41']}`, expected: `{"a":[1, 2],"b":["cA"]}`},
+		{input: `[0x1F, +1, .5, 5., -0xFFFFFFFFFFFFFFFFFF, 1e2]`, expected: `[31,1,0.5,5,-4722366482869645213695,1e2]`},
+		{input: "[1,\v2]", expected: `[1,2]`},
+		{input: `[[1,], [2]]`, expected: `[[1],[2]]`},
+		{input: `'a'`, expected: `"a"`},
+		{input: `[1e400, -1e400]`, expected: `[1e400, -1e400]`},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			root := Must(UnmarshalJSON5([]byte(test.input)))
+			if string(root.Source()) != test.input {
+				t.Errorf("Source() wrong value: %s", root.Source())
+			}
+			result, err := Marshal(root)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("Marshal() wrong result: %s", result)
+			}
+			if !Valid(result) {
+				t.Errorf("Marshal() result is not valid JSON: %s", result)
+			}
+			// json.Marshal validates the result of MarshalJSON
+			if _, err = json.Marshal(root); err != nil {
+				t.Errorf("json.Marshal() unexpected error: %s", err)
+			}
+			if result, err = MarshalIndent(root, "", "  "); err != nil || !Valid(result) {
+				t.Errorf("MarshalIndent() wrong result: %s, %v", result, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON5_marshalNaN(t *testing.T) {
+	for _, input := range []string{`[NaN]`, `{a: Infinity}`, `-Infinity`} {
+		root := Must(UnmarshalJSON5([]byte(input)))
+		if result, err := Marshal(root); err == nil {
+			t.Errorf("Marshal() expected error, got: %s", result)
+		}
+		if result, err := json.Marshal(root); err == nil {
+			t.Errorf("json.Marshal() expected error, got: %s", result)
+		}
+	}
+}
+
+func TestUnmarshalJSON5_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		_type ErrorType
+		index int
+	}{
+		{name: "empty", input: ``, _type: UnexpectedEOF, index: 0},
+		{name: "comment only", input: `// comment`, _type: UnexpectedEOF, index: 10},
+		{name: "unclosed comment", input: `[1 /* comment`, _type: UnexpectedEOF, index: 13},
+		{name: "unclosed array", input: `[1,`, _type: UnexpectedEOF, index: 3},
+		{name: "unclosed string", input: `'abc`, _type: UnexpectedEOF, index: 4},
+		{name: "double comma", input: `[1,,2]`, _type: WrongSymbol, index: 3},
+		{name: "leading comma", input: `{,}`, _type: WrongSymbol, index: 1},
+		{name: "missed colon", input: `{a 1}`, _type: WrongSymbol, index: 3},
+		{name: "missed comma", input: `[1 2]`, _type: WrongSymbol, index: 3},
+		{name: "wrong key", input: `{1: 1}`, _type: WrongSymbol, index: 1},
+		{name: "leading zero", input: `01`, _type: WrongSymbol, index: 1},
+		{name: "wrong hex", input: `0xZ`, _type: WrongSymbol, index: 2},
+		{name: "wrong literal", input: `[trux]`, _type: WrongSymbol, index: 1},
+		{name: "short literal", input: `[tru`, _type: UnexpectedEOF, index: 4},
+		{name: "literal suffix", input: `[nullify]`, _type: WrongSymbol, index: 5},
+		{name: "number suffix", input: `[1a]`, _type: WrongSymbol, index: 2},
+		{name: "new line in string", input: "'a\nb'", _type: WrongSymbol, index: 2},
+		{name: "wrong escape", input: `['\x4']`, _type: WrongSymbol, index: 1},
+		{name: "second value", input: `1 2`, _type: WrongSymbol, index: 2},
+		{name: "wrong exponent", input: `1e`, _type: UnexpectedEOF, index: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := UnmarshalJSON5([]byte(test.input))
+			var jerr Error
+			if !errors.As(err, &jerr) {
+				t.Fatalf("UnmarshalJSON5() expected Error, got: %v, %s", err, root)
+			}
+			if jerr.Type != test._type || jerr.Index != test.index {
+				t.Errorf("UnmarshalJSON5() wrong error: %s", jerr)
+			}
+			if jerr.Line == 0 || jerr.Column != test.index+1 || jerr.Snippet == "" {
+				t.Errorf("UnmarshalJSON5() wrong position: %d:%d %q", jerr.Line, jerr.Column, jerr.Snippet)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON5_options(t *testing.T) {
+	_, err := UnmarshalWithOptions([]byte(`{a: {b: [1]}}`), Options{JSON5: true, MaxDepth: 2})
+	if err == nil || err.(Error).Type != LimitExceeded {
+		t.Errorf("UnmarshalWithOptions() wrong error: %v", err)
+	}
+	_, err = UnmarshalJSON5(bytes.Repeat([]byte(`[`), 5<<20))
+	if err == nil || err.(Error).Type != LimitExceeded || err.(Error).Index != json5MaxDepth {
+		t.Errorf("UnmarshalJSON5() wrong error: %v", err)
+	}
+	if _, err = UnmarshalJSON5([]byte(strings.Repeat(`[`, json5MaxDepth) + strings.Repeat(`]`, json5MaxDepth))); err != nil {
+		t.Errorf("UnmarshalJSON5() unexpected error: %v", err)
+	}
+	_, err = UnmarshalWithOptions([]byte(`{a: 1, 'a': 2}`), Options{JSON5: true, DuplicateKeys: DuplicateKeyError})
+	if err == nil || err.(Error).Type != DuplicateKey || err.(Error).Index != 7 {
+		t.Errorf("UnmarshalWithOptions() wrong error: %v", err)
+	}
+	root, err := UnmarshalWithOptions([]byte(`{a: 1, a: 2}`), Options{JSON5: true, DuplicateKeys: DuplicateKeyFirstWins})
+	if err != nil {
+		t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+	if value := root.MustKey("a").MustNumeric(); value != 1 {
+		t.Errorf("UnmarshalWithOptions() wrong value: %v", value)
+	}
+}
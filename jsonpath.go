@@ -408,7 +408,7 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 						}
 
 						for i := ikeys[0]; i < ikeys[1]; i += ikeys[2] {
-							value, ok := element.children[strconv.Itoa(i)]
+							value, ok := element.child(strconv.Itoa(i))
 							if ok {
 								temporary = append(temporary, value)
 							}
@@ -422,7 +422,7 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 						}
 
 						for i := ikeys[0]; i > ikeys[1]; i += ikeys[2] {
-							value, ok := element.children[strconv.Itoa(i)]
+							value, ok := element.child(strconv.Itoa(i))
 							if ok {
 								temporary = append(temporary, value)
 							}
@@ -477,7 +477,7 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 						if err != nil {
 							return nil, errorRequest("wrong type convert: %s", err.Error())
 						}
-						value = element.children[key]
+						value, _ = element.child(key)
 					case Numeric:
 						num, err = temp.getInteger()
 						if err == nil { // INTEGER
@@ -493,7 +493,7 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 							}
 							key = strconv.FormatFloat(float, 'g', -1, 64)
 						}
-						value = element.children[key]
+						value, _ = element.child(key)
 					case Bool:
 						ok, err = temp.GetBool()
 						if err != nil {
@@ -544,7 +544,7 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 							} else {
 								num = getPositiveIndex(int(fkeys[0]), element.Size())
 								key = strconv.Itoa(num)
-								value, ok = element.children[key]
+								value, ok = element.child(key)
 							}
 						} else {
 							key, _ = str(key)
@@ -555,13 +555,13 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 							} else {
 								num = getPositiveIndex(num, element.Size())
 								key = strconv.Itoa(num)
-								value, ok = element.children[key]
+								value, ok = element.child(key)
 							}
 						}
 
 					} else if element.IsObject() {
 						key, _ = str(key)
-						value, ok = element.children[key]
+						value, ok = element.child(key)
 					}
 					if ok {
 						temporary = append(temporary, value)
@@ -0,0 +1,145 @@
+package ajson
+
+import (
+	"strconv"
+	"sync"
+)
+
+// loader holds the state of a lazy container, which children weren't created yet.
+type loader struct {
+	once      sync.Once
+	firstWins bool
+}
+
+// unmarshalLazy validates the whole data, but creates only the root node. Children of the containers will be
+// created on the first access, see Options.Lazy.
+func unmarshalLazy(data []byte, options Options) (root *Node, err error) {
+	if options.JSON5 {
+		return nil, errorRequest("lazy mode doesn't support JSON5")
+	}
+	if options.DuplicateKeys == DuplicateKeyError {
+		return nil, errorRequest("lazy mode doesn't support DuplicateKeyError policy")
+	}
+	var s scanner
+	s.reset()
+	for i, c := range data {
+		if !s.feed(c) {
+			return nil, errorIn(data, WrongSymbol, i)
+		}
+		if options.MaxDepth > 0 && len(s.stack) > options.MaxDepth {
+			return nil, errorLimit(i, "max depth %d", options.MaxDepth)
+		}
+	}
+	if !s.complete() {
+		return nil, errorIn(data, UnexpectedEOF, len(data))
+	}
+
+	start, end := 0, len(data)
+	for isSpace(data[start]) {
+		start++
+	}
+	for isSpace(data[end-1]) {
+		end--
+	}
+	root = &Node{
+		data:    &data,
+		borders: [2]int{start, end},
+		_type:   lazyType(data[start]),
+	}
+	root.lazy(options.DuplicateKeys == DuplicateKeyFirstWins)
+	return root, nil
+}
+
+// isSpace returns true if symbol is a JSON whitespace
+func isSpace(c byte) bool {
+	return c == skipS || c == skipR || c == skipN || c == skipT
+}
+
+// lazyType returns the type of value by its first symbol
+func lazyType(c byte) NodeType {
+	switch c {
+	case bracesL:
+		return Object
+	case bracketL:
+		return Array
+	case quotes:
+		return String
+	case 't', 'f':
+		return Bool
+	case 'n':
+		return Null
+	}
+	return Numeric
+}
+
+// lazy prepares the container node to create its children on demand
+func (n *Node) lazy(firstWins bool) {
+	if n.isContainer() {
+		n.children = make(map[string]*Node)
+		n.loader = &loader{firstWins: firstWins}
+	}
+}
+
+// load creates children of the lazy container, if they weren't created yet. It's safe for concurrent use.
+func (n *Node) load() {
+	if n != nil && n.loader != nil {
+		n.loader.once.Do(n.expand)
+	}
+}
+
+// expand creates all direct children of the lazy container, nested containers stay lazy.
+func (n *Node) expand() {
+	tokenizer := &Tokenizer{
+		data:  (*n.data)[:n.borders[1]],
+		index: n.borders[0],
+	}
+	var (
+		key   string
+		child *Node
+	)
+	for {
+		token, err := tokenizer.Next()
+		if err != nil {
+			// data was validated on Unmarshal
+			return
+		}
+		if token.Depth != 1 {
+			continue
+		}
+		switch token.Type {
+		case TokenKey:
+			key, _ = unquote(token.Raw, quotes)
+			continue
+		case TokenObjectEnd, TokenArrayEnd:
+			child.borders[1] = token.End
+			continue
+		}
+
+		child = &Node{
+			parent:  n,
+			data:    n.data,
+			borders: [2]int{token.Start, token.End},
+			_type:   lazyType(token.Raw[0]),
+		}
+		if token.Type == TokenObjectStart || token.Type == TokenArrayStart {
+			child.borders[1] = 0
+			child.lazy(n.loader.firstWins)
+		}
+		if n.IsArray() {
+			index := len(n.children)
+			child.index = &index
+			n.children[strconv.Itoa(index)] = child
+		} else if _, ok := n.children[key]; !ok || !n.loader.firstWins {
+			ckey := key
+			child.key = &ckey
+			n.setChild(key, child)
+		}
+	}
+}
+
+// child returns the child of the container by its key or index
+func (n *Node) child(key string) (value *Node, ok bool) {
+	n.load()
+	value, ok = n.children[key]
+	return
+}
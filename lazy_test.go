@@ -0,0 +1,218 @@
+package ajson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func ExampleOptions_lazy() {
+	data := []byte(`{"meta": {"id": 42}, "items": [{"name": "first"}, {"name": "second"}]}`)
+	root, err := UnmarshalWithOptions(data, Options{Lazy: true})
+	if err != nil {
+		panic(err)
+	}
+	// only the root and the children of "meta" are created here
+	fmt.Println(root.MustKey("meta").MustKey("id").MustNumeric())
+	// Output:
+	// 42
+}
+
+func TestUnmarshalWithOptions_lazy(t *testing.T) {
+	tests := []string{
+		`{"a":1,"b":[1,2,{"c":null}],"d":{"e":"f\"g","h":{}}}`,
+		` [ true , false , [ [ ] , { } ] , -1.5e3 ] `,
+		`"string"`,
+		`123`,
+		`{"z":1,"a":2}`,
+		`[]`,
+		string(jsonPathTestData),
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			eager := Must(Unmarshal([]byte(test)))
+			lazy, err := UnmarshalWithOptions([]byte(test), Options{Lazy: true})
+			if err != nil {
+				t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+			}
+			if lazy.Type() != eager.Type() || string(lazy.Source()) != string(eager.Source()) {
+				t.Errorf("UnmarshalWithOptions() wrong root: %s", lazy.Source())
+			}
+			expected, err := eager.Unpack()
+			if err != nil {
+				t.Fatalf("Unpack() unexpected error: %s", err)
+			}
+			result, err := lazy.Unpack()
+			if err != nil {
+				t.Fatalf("Unpack() unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Unpack() wrong result: %v", result)
+			}
+			if !reflect.DeepEqual(lazy.Keys(), eager.Keys()) {
+				t.Errorf("Keys() wrong result: %v", lazy.Keys())
+			}
+			if string(mustBytes(MarshalWithOptions(lazy, MarshalOptions{SortKeys: true}))) != string(mustBytes(MarshalWithOptions(eager, MarshalOptions{SortKeys: true}))) {
+				t.Errorf("MarshalWithOptions() wrong result")
+			}
+		})
+	}
+}
+
+func TestUnmarshalWithOptions_lazyJSONPath(t *testing.T) {
+	paths := []string{
+		"$..price",
+		"$.store.book[*].author",
+		"$..book[?(@.isbn)]",
+		"$['store']['book'][-2,(@.length-1)]",
+		"$..[1:4]",
+		"$.store.*",
+		"$[('store')][('bo'+'ok')][(@.length - 1)]",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			expected, err := JSONPath(jsonPathTestData, path)
+			if err != nil {
+				t.Fatalf("JSONPath() unexpected error: %s", err)
+			}
+			root := Must(UnmarshalWithOptions(jsonPathTestData, Options{Lazy: true}))
+			result, err := root.JSONPath(path)
+			if err != nil {
+				t.Fatalf("JSONPath() unexpected error: %s", err)
+			}
+			if fullPath(result) != fullPath(expected) {
+				t.Errorf("JSONPath() wrong result:\n%s\n%s", fullPath(result), fullPath(expected))
+			}
+			for i := range result {
+				if string(result[i].Source()) != string(expected[i].Source()) {
+					t.Errorf("JSONPath() wrong value: %s", result[i].Source())
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalWithOptions_lazyOnDemand(t *testing.T) {
+	root := Must(UnmarshalWithOptions([]byte(`{"a":{"b":[1,2]},"c":{"d":3}}`), Options{Lazy: true}))
+	if len(root.children) != 0 {
+		t.Errorf("children are created before access: %d", len(root.children))
+	}
+	a := root.MustKey("a")
+	if len(root.children) != 2 {
+		t.Errorf("children aren't created on access: %d", len(root.children))
+	}
+	if len(a.children) != 0 || len(root.MustKey("c").children) != 0 {
+		t.Errorf("nested children are created before access")
+	}
+	if string(a.Source()) != `{"b":[1,2]}` {
+		t.Errorf("Source() wrong value: %s", a.Source())
+	}
+	if value := a.MustKey("b").MustIndex(-1).MustNumeric(); value != 2 {
+		t.Errorf("MustIndex() wrong value: %v", value)
+	}
+	if line, column := a.MustKey("b").Position(); line != 1 || column != 11 {
+		t.Errorf("Position() wrong value: %d:%d", line, column)
+	}
+	if len(root.MustKey("c").children) != 0 {
+		t.Errorf("nested children are created before access")
+	}
+}
+
+func TestUnmarshalWithOptions_lazyMutations(t *testing.T) {
+	root := Must(UnmarshalWithOptions([]byte(`{"a":{"b":[1,2]},"c":{"d":3},"e":[]}`), Options{Lazy: true}))
+	if err := root.MustKey("a").MustKey("b").AppendArray(NumericNode("", 3)); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	if err := root.MustKey("e").AppendArray(NullNode("")); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	if err := root.AppendObject("f", Must(UnmarshalWithOptions([]byte(`{"g":[true]}`), Options{Lazy: true}))); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	clone := root.Clone()
+	if err := root.DeleteKey("c"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"a":{"b":[1,2,3]},"e":[null],"f":{"g":[true]}}` {
+		t.Errorf("String() wrong result: %s", result)
+	}
+	if result := clone.String(); result != `{"a":{"b":[1,2,3]},"c":{"d":3},"e":[null],"f":{"g":[true]}}` {
+		t.Errorf("String() wrong result of clone: %s", result)
+	}
+	if err := root.MustKey("a").SetNode(clone.MustKey("c")); err != nil {
+		t.Fatalf("SetNode() unexpected error: %s", err)
+	}
+	if result := root.MustKey("a").String(); result != `{"d":3}` {
+		t.Errorf("String() wrong result: %s", result)
+	}
+}
+
+func TestUnmarshalWithOptions_lazyDuplicates(t *testing.T) {
+	data := []byte(`{"a":1,"b":2,"a":3}`)
+	root := Must(UnmarshalWithOptions(data, Options{Lazy: true}))
+	if value := root.MustKey("a").MustNumeric(); value != 3 {
+		t.Errorf("last wins: wrong value %v", value)
+	}
+	root = Must(UnmarshalWithOptions(data, Options{Lazy: true, DuplicateKeys: DuplicateKeyFirstWins}))
+	if value := root.MustKey("a").MustNumeric(); value != 1 {
+		t.Errorf("first wins: wrong value %v", value)
+	}
+	if !reflect.DeepEqual(root.Keys(), []string{"a", "b"}) {
+		t.Errorf("Keys() wrong result: %v", root.Keys())
+	}
+}
+
+func TestUnmarshalWithOptions_lazyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options Options
+	}{
+		{name: "empty", input: ``},
+		{name: "blank", input: `  `},
+		{name: "unclosed", input: `{"a":[1,{}`},
+		{name: "wrong symbol", input: `{"a":[1,}]}`},
+		{name: "deep error", input: `[[[[[[1,2,3,nul]]]]]]`},
+		{name: "second value", input: `[] []`},
+		{name: "max depth", input: `[[[1]]]`, options: Options{MaxDepth: 2}},
+		{name: "max bytes", input: `[1,2,3]`, options: Options{MaxBytes: 5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, expected := UnmarshalWithOptions([]byte(test.input), test.options)
+			test.options.Lazy = true
+			_, err := UnmarshalWithOptions([]byte(test.input), test.options)
+			var jerr, eerr Error
+			if !errors.As(err, &jerr) || !errors.As(expected, &eerr) {
+				t.Fatalf("UnmarshalWithOptions() expected Error, got: %v", err)
+			}
+			if jerr.Type != eerr.Type || (jerr.Type != UnexpectedEOF && jerr.Index != eerr.Index) {
+				t.Errorf("UnmarshalWithOptions() wrong error: %s, expected %s", jerr, eerr)
+			}
+		})
+	}
+
+	for _, options := range []Options{{Lazy: true, JSON5: true}, {Lazy: true, DuplicateKeys: DuplicateKeyError}} {
+		if _, err := UnmarshalWithOptions([]byte(`{}`), options); err == nil || err.(Error).Type != WrongRequest {
+			t.Errorf("UnmarshalWithOptions() wrong error: %v", err)
+		}
+	}
+}
+
+func TestUnmarshalWithOptions_lazyConcurrent(t *testing.T) {
+	root := Must(UnmarshalWithOptions(jsonPathTestData, Options{Lazy: true}))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := root.JSONPath("$..price")
+			if err != nil || len(result) != 5 {
+				t.Errorf("JSONPath() wrong result: %v, %v", result, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,136 @@
+package ajson
+
+// MergePatch applies JSON Merge Patch (RFC 7386) document to the target node and returns it: members of the patch
+// object are merged into the target object recursively, null values delete the members, any other patch value
+// replaces the target entirely.
+//
+// The target node is updated in place, with the clones of the patch values. If target is nil, the new node is created.
+func MergePatch(target, patch *Node) (*Node, error) {
+	if patch == nil {
+		return nil, errorUnparsed()
+	}
+	if target == nil {
+		target = NullNode("")
+	}
+	if err := mergePatch(target, patch); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// mergePatch merges the patch into the target
+func mergePatch(target, patch *Node) error {
+	if !patch.IsObject() {
+		return target.SetNode(patch)
+	}
+	if !target.IsObject() {
+		if err := target.SetObject(map[string]*Node{}); err != nil {
+			return err
+		}
+	}
+	for _, key := range patch.Keys() {
+		value, _ := patch.child(key)
+		child, ok := target.child(key)
+		if value.IsNull() {
+			if ok {
+				if err := target.remove(child); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !ok {
+			child = NullNode(key)
+			if err := target.AppendObject(key, child); err != nil {
+				return err
+			}
+		}
+		if err := mergePatch(child, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateMergePatch returns JSON Merge Patch (RFC 7386) document, which turns the original node into the modified
+// one, when applied with MergePatch. Members of objects, that were not changed, are omitted.
+//
+// Merge patch can't set the member of an object to null, so it returns an error if the modified node contains
+// such member, that should be a part of the patch.
+func CreateMergePatch(original, modified *Node) (*Node, error) {
+	if original == nil || modified == nil {
+		return nil, errorUnparsed()
+	}
+	if !original.IsObject() || !modified.IsObject() {
+		return mergeValue(modified)
+	}
+	result := ObjectNode("", map[string]*Node{})
+	for _, key := range modified.Keys() {
+		value, _ := modified.child(key)
+		previous, _ := original.child(key)
+		patch, err := mergeMember(previous, value)
+		if err != nil {
+			return nil, err
+		}
+		if patch == nil {
+			continue
+		}
+		if err = result.AppendObject(key, patch); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range original.Keys() {
+		if !modified.HasKey(key) {
+			if err := result.AppendObject(key, NullNode(key)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// mergeMember returns the patch of the object member, or nil if it wasn't changed. Previous value is nil for the new
+// member.
+func mergeMember(previous, value *Node) (*Node, error) {
+	if previous != nil {
+		if previous.IsObject() && value.IsObject() {
+			patch, err := CreateMergePatch(previous, value)
+			if err != nil || patch.Size() == 0 {
+				return nil, err
+			}
+			return patch, nil
+		}
+		if equal(previous, value) {
+			return nil, nil
+		}
+	}
+	if value.IsNull() {
+		return nil, errorRequest("null value at %s can't be set by merge patch", value.Path())
+	}
+	return mergeValue(value)
+}
+
+// mergeValue returns the clone of the node, which is used as the value of merge patch
+func mergeValue(node *Node) (*Node, error) {
+	if err := mergeNulls(node); err != nil {
+		return nil, err
+	}
+	return node.Clone(), nil
+}
+
+// mergeNulls returns an error if the object or any nested object contains null value, because it will be treated as
+// deletion by merge patch. Arrays are copied by merge patch as they are, so nulls are allowed inside them.
+func mergeNulls(node *Node) error {
+	if !node.IsObject() {
+		return nil
+	}
+	for _, child := range node.Inheritors() {
+		if child.IsNull() {
+			return errorRequest("null value at %s can't be set by merge patch", child.Path())
+		}
+		if err := mergeNulls(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleMergePatch() {
+	root := Must(Unmarshal([]byte(`{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "This will be unchanged"}`)))
+	patch := Must(Unmarshal([]byte(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`)))
+	result, err := MergePatch(root, patch)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(result)
+
+	original := Must(Unmarshal([]byte(`{"a": "b", "c": {"d": "e", "f": "g"}}`)))
+	modified := Must(Unmarshal([]byte(`{"a": "z", "c": {"d": "e"}}`)))
+	patch, err = CreateMergePatch(original, modified)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(patch)
+	// Output:
+	// {"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}
+	// {"a":"z","c":{"f":null}}
+}
+
+// rfc7386 are the test cases from RFC 7386, Appendix A
+var rfc7386 = []struct {
+	original string
+	patch    string
+	result   string
+}{
+	{original: `{"a":"b"}`, patch: `{"a":"c"}`, result: `{"a":"c"}`},
+	{original: `{"a":"b"}`, patch: `{"b":"c"}`, result: `{"a":"b","b":"c"}`},
+	{original: `{"a":"b"}`, patch: `{"a":null}`, result: `{}`},
+	{original: `{"a":"b","b":"c"}`, patch: `{"a":null}`, result: `{"b":"c"}`},
+	{original: `{"a":["b"]}`, patch: `{"a":"c"}`, result: `{"a":"c"}`},
+	{original: `{"a":"c"}`, patch: `{"a":["b"]}`, result: `{"a":["b"]}`},
+	{original: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, result: `{"a":{"b":"d"}}`},
+	{original: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, result: `{"a":[1]}`},
+	{original: `["a","b"]`, patch: `["c","d"]`, result: `["c","d"]`},
+	{original: `{"a":"b"}`, patch: `["c"]`, result: `["c"]`},
+	{original: `{"a":"foo"}`, patch: `null`, result: `null`},
+	{original: `{"a":"foo"}`, patch: `"bar"`, result: `"bar"`},
+	{original: `{"e":null}`, patch: `{"a":1}`, result: `{"e":null,"a":1}`},
+	{original: `[1,2]`, patch: `{"a":"b","c":null}`, result: `{"a":"b"}`},
+	{original: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, result: `{"a":{"bb":{}}}`},
+}
+
+func TestMergePatch(t *testing.T) {
+	for _, test := range rfc7386 {
+		t.Run(test.original+" + "+test.patch, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				root := Must(UnmarshalWithOptions([]byte(test.original), Options{Lazy: lazy}))
+				patch := Must(Unmarshal([]byte(test.patch)))
+				result, err := MergePatch(root, patch)
+				if err != nil {
+					t.Fatalf("MergePatch() unexpected error: %s", err)
+				}
+				if result != root {
+					t.Errorf("MergePatch() target was not updated in place")
+				}
+				if result.String() != test.result {
+					t.Errorf("MergePatch() wrong result: %s", result)
+				}
+				if patch.String() != test.patch || patch.IsDirty() {
+					t.Errorf("MergePatch() patch was changed: %s", patch)
+				}
+			}
+		})
+	}
+}
+
+func TestMergePatch_child(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": 1, "c": 2}, "d": [3]}`)))
+	if _, err := MergePatch(root.MustKey("a"), Must(Unmarshal([]byte(`{"b": null, "e": {"f": [null]}}`)))); err != nil {
+		t.Fatalf("MergePatch() unexpected error: %s", err)
+	}
+	if _, err := MergePatch(root.MustKey("d"), Must(Unmarshal([]byte(`[4]`)))); err != nil {
+		t.Fatalf("MergePatch() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"a":{"c":2,"e":{"f":[null]}},"d":[4]}` {
+		t.Errorf("MergePatch() wrong result: %s", result)
+	}
+
+	result, err := MergePatch(nil, Must(Unmarshal([]byte(`{"a": {"b": null, "c": true}}`))))
+	if err != nil {
+		t.Fatalf("MergePatch() unexpected error: %s", err)
+	}
+	if result.String() != `{"a":{"c":true}}` {
+		t.Errorf("MergePatch() wrong result: %s", result)
+	}
+	if _, err = MergePatch(root, nil); err == nil {
+		t.Errorf("MergePatch() expected error")
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := []struct {
+		original string
+		modified string
+		expected string
+	}{
+		{original: `{"a": 1, "b": [1, 2], "c": {"d": true}}`, modified: `{"a": 1, "b": [1, 2], "c": {"d": true}}`, expected: `{}`},
+		{original: `{"a": 1, "b": 2}`, modified: `{"b": 2.0, "c": 3, "a": 1}`, expected: `{"c":3}`},
+		{original: `{"a": {"b": {"c": 1, "d": 2}}}`, modified: `{"a": {"b": {"c": 1}, "e": {"f": "g"}}}`, expected: `{"a":{"b":{"d":null},"e":{"f": "g"}}}`},
+		{original: `{"a": [1, {"b": 2}]}`, modified: `{"a": [1, {"b": null}]}`, expected: `{"a":[1, {"b": null}]}`},
+		{original: `{"a": "b"}`, modified: `{"a": {"b": "c"}}`, expected: `{"a":{"b": "c"}}`},
+		{original: `{"a": null}`, modified: `{"a": 1}`, expected: `{"a":1}`},
+		{original: `{"id": 12345678901234567890}`, modified: `{"id": 12345678901234567891}`, expected: `{"id":12345678901234567891}`},
+		{original: `{"id": [12345678901234567890]}`, modified: `{"id": [12345678901234567891]}`, expected: `{"id":[12345678901234567891]}`},
+		{original: `{"id": 1e20}`, modified: `{"id": 100000000000000000000}`, expected: `{}`},
+		{original: `{"a": "b"}`, modified: `[1]`, expected: `[1]`},
+		{original: `[1]`, modified: `{"a": "b"}`, expected: `{"a": "b"}`},
+		{original: `{"a": 1}`, modified: `null`, expected: `null`},
+	}
+	for _, test := range tests {
+		t.Run(test.original+" -> "+test.modified, func(t *testing.T) {
+			original := Must(Unmarshal([]byte(test.original)))
+			modified := Must(Unmarshal([]byte(test.modified)))
+			patch, err := CreateMergePatch(original, modified)
+			if err != nil {
+				t.Fatalf("CreateMergePatch() unexpected error: %s", err)
+			}
+			if patch.String() != test.expected {
+				t.Errorf("CreateMergePatch() wrong result: %s", patch)
+			}
+			result, err := MergePatch(original, patch)
+			if err != nil {
+				t.Fatalf("MergePatch() unexpected error: %s", err)
+			}
+			if ok, err := result.Eq(modified); err != nil || !ok {
+				t.Errorf("MergePatch() wrong result: %s", result)
+			}
+		})
+	}
+
+	for _, test := range rfc7386 {
+		if test.original[0] != '{' || test.result[0] != '{' || test.original == `{"e":null}` {
+			continue
+		}
+		original := Must(Unmarshal([]byte(test.original)))
+		patch, err := CreateMergePatch(original, Must(Unmarshal([]byte(test.result))))
+		if err != nil {
+			t.Fatalf("CreateMergePatch() unexpected error: %s", err)
+		}
+		if result := Must(MergePatch(original, patch)).String(); result != test.result {
+			t.Errorf("CreateMergePatch() wrong patch %s for %s: %s", patch, test.original, result)
+		}
+	}
+}
+
+func TestCreateMergePatch_errors(t *testing.T) {
+	tests := []struct {
+		original string
+		modified string
+		message  string
+	}{
+		{original: `{"a": 1}`, modified: `{"a": null}`, message: `wrong request: null value at $['a'] can't be set by merge patch`},
+		{original: `{}`, modified: `{"a": null}`, message: `wrong request: null value at $['a'] can't be set by merge patch`},
+		{original: `{}`, modified: `{"a": {"b": [{"c": null}], "d": null}}`, message: `wrong request: null value at $['a']['d'] can't be set by merge patch`},
+		{original: `{"a": {"b": 1}}`, modified: `{"a": {"b": null}}`, message: `wrong request: null value at $['a']['b'] can't be set by merge patch`},
+		{original: `[]`, modified: `{"a": null}`, message: `wrong request: null value at $['a'] can't be set by merge patch`},
+	}
+	for _, test := range tests {
+		t.Run(test.original+" -> "+test.modified, func(t *testing.T) {
+			_, err := CreateMergePatch(Must(Unmarshal([]byte(test.original))), Must(Unmarshal([]byte(test.modified))))
+			if err == nil {
+				t.Fatalf("CreateMergePatch() expected error")
+			}
+			if err.Error() != test.message {
+				t.Errorf("CreateMergePatch() wrong error: %s", err)
+			}
+		})
+	}
+	if _, err := CreateMergePatch(nil, NullNode("")); err == nil {
+		t.Errorf("CreateMergePatch() expected error")
+	}
+}
@@ -2,6 +2,7 @@ package ajson
 
 import (
 	"math"
+	"math/big"
 	"sort"
 	"strconv"
 	"sync/atomic"
@@ -24,6 +25,8 @@ import (
 type Node struct {
 	parent   *Node
 	children map[string]*Node
+	keys     []string
+	loader   *loader
 	key      *string
 	index    *int
 	_type    NodeType
@@ -31,6 +34,8 @@ type Node struct {
 	borders  [2]int
 	value    atomic.Value
 	dirty    bool
+	// lenient is true for the node, parsed from JSON5 source, which is not valid JSON
+	lenient bool
 }
 
 // NodeType is a kind of reflection of JSON type to a type of golang.
@@ -133,11 +138,14 @@ func ObjectNode(key string, value map[string]*Node) (current *Node) {
 		dirty:    true,
 	}
 	if value != nil {
+		current.keys = make([]string, 0, len(value))
 		for key, val := range value {
 			vkey := key
 			val.parent = current
 			val.key = &vkey
+			current.keys = append(current.keys, key)
 		}
+		sort.Strings(current.keys)
 		current.value.Store(value)
 	} else {
 		current.children = make(map[string]*Node)
@@ -166,7 +174,7 @@ func newNode(parent *Node, buf *buffer, _type NodeType, key **string) (current *
 			if *key == nil {
 				err = errorSymbol(buf)
 			} else {
-				parent.children[**key] = current
+				parent.setChild(**key, current)
 			}
 		} else {
 			err = errorSymbol(buf)
@@ -224,6 +232,50 @@ func (n *Node) String() string {
 	return string(val)
 }
 
+// MarshalJSON is implementation of json.Marshaler interface, returns the same result as Marshal.
+// Nil and zero-valued nodes are marshaled as null.
+//
+// Please note: for the Node fields (not *Node), the outer struct should be marshaled by pointer.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	if n == nil || (n.data == nil && !n.dirty) {
+		return append([]byte(nil), _null...), nil
+	}
+	return Marshal(n)
+}
+
+// UnmarshalJSON is implementation of json.Unmarshaler interface, replaces current node with the parsed data.
+// Data is copied, as UnmarshalSafe does.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	root, err := UnmarshalSafe(data)
+	if err != nil {
+		return err
+	}
+	*n = *root
+	for _, child := range n.children {
+		child.parent = n
+	}
+	return nil
+}
+
+// Offset returns borders of the current node in the source data: the first byte and the byte right after the last one.
+// It returns -1, -1 if the node wasn't parsed from the data (e.g. created by constructor or updated).
+func (n *Node) Offset() (start, end int) {
+	if n == nil || n.data == nil || !n.ready() || (n.dirty && !n.isContainer()) {
+		return -1, -1
+	}
+	return n.borders[0], n.borders[1]
+}
+
+// Position returns line and column (both starts from 1) of the current node in the source data.
+// It returns 0, 0 if the node wasn't parsed from the data.
+func (n *Node) Position() (line, column int) {
+	start, _ := n.Offset()
+	if start < 0 {
+		return 0, 0
+	}
+	return location(*n.data, start)
+}
+
 // Type will return type of current node.
 func (n *Node) Type() NodeType {
 	if n == nil {
@@ -259,17 +311,25 @@ func (n *Node) Size() int {
 	if n == nil {
 		return 0
 	}
+	n.load()
 	return len(n.children)
 }
 
-// Keys will return count all keys of children of current node, please check, that parent of this node has an Object type.
+// Keys will return all keys of children of current node, please check, that parent of this node has an Object type.
+// Keys of an Object are returned in the insertion order: as they were parsed or appended.
 func (n *Node) Keys() (result []string) {
 	if n == nil {
 		return nil
 	}
+	n.load()
+	if n.IsObject() {
+		result = make([]string, len(n.keys))
+		copy(result, n.keys)
+		return
+	}
 	result = make([]string, 0, len(n.children))
-	for key := range n.children {
-		result = append(result, key)
+	for i := 0; i < len(n.children); i++ {
+		result = append(result, strconv.Itoa(i))
 	}
 	return
 }
@@ -376,7 +436,7 @@ func (n *Node) getValue() (value interface{}, err error) {
 			var ok bool
 			value, ok = unquote(n.Source(), quotes)
 			if !ok {
-				return "", errorAt(n.borders[0], (*n.data)[n.borders[0]])
+				return "", errorIn(*n.data, WrongSymbol, n.borders[0])
 			}
 			n.value.Store(value)
 		case Bool:
@@ -387,6 +447,7 @@ func (n *Node) getValue() (value interface{}, err error) {
 			value = b == 't' || b == 'T'
 			n.value.Store(value)
 		case Array:
+			n.load()
 			children := make([]*Node, len(n.children))
 			for _, child := range n.children {
 				children[*child.index] = child
@@ -394,6 +455,7 @@ func (n *Node) getValue() (value interface{}, err error) {
 			value = children
 			n.value.Store(value)
 		case Object:
+			n.load()
 			result := make(map[string]*Node)
 			for key, child := range n.children {
 				result[key] = child
@@ -435,6 +497,131 @@ func (n *Node) GetNumeric() (value float64, err error) {
 	return value, nil
 }
 
+// GetNumberString returns the number as it was written in the source, if current type is Numeric, else: WrongType error.
+// For nodes without source, it returns the shortest representation of the float64 value.
+func (n *Node) GetNumberString() (value string, err error) {
+	if n == nil {
+		return "", errorUnparsed()
+	}
+	if n._type != Numeric {
+		return value, errorType()
+	}
+	if literal := n.literal(); literal != nil {
+		return string(literal), nil
+	}
+	number, err := n.GetNumeric()
+	if err != nil {
+		return "", err
+	}
+	return string(formatFloat(number)), nil
+}
+
+// GetInt64 returns int64 exactly as it was written in the source, if current type is Numeric, else: WrongType error.
+// It returns WrongRequest error if the value is not an integer, or it doesn't fit into int64.
+func (n *Node) GetInt64() (value int64, err error) {
+	literal, err := n.GetNumberString()
+	if err != nil {
+		return 0, err
+	}
+	if value, err = strconv.ParseInt(literal, 0, 64); err == nil {
+		return value, nil
+	}
+	integer, err := n.getBigInt(64)
+	if err != nil {
+		return 0, err
+	}
+	if integer == nil || !integer.IsInt64() {
+		return 0, errorRequest("node is not INT64")
+	}
+	return integer.Int64(), nil
+}
+
+// GetUint64 returns uint64 exactly as it was written in the source, if current type is Numeric, else: WrongType error.
+// It returns WrongRequest error if the value is not a positive integer, or it doesn't fit into uint64.
+func (n *Node) GetUint64() (value uint64, err error) {
+	literal, err := n.GetNumberString()
+	if err != nil {
+		return 0, err
+	}
+	if value, err = strconv.ParseUint(literal, 0, 64); err == nil {
+		return value, nil
+	}
+	integer, err := n.getBigInt(64)
+	if err != nil {
+		return 0, err
+	}
+	if integer == nil || !integer.IsUint64() {
+		return 0, errorRequest("node is not UINT64")
+	}
+	return integer.Uint64(), nil
+}
+
+// maxBigIntBits is the limit of the integer, written in the exponent form, for GetBigInt
+const maxBigIntBits = 1 << 16
+
+// GetBigInt returns *big.Int exactly as it was written in the source, if current type is Numeric, else: WrongType error.
+// It returns WrongRequest error if the value is not an integer (e.g. `1.5`), but `1e3` or `1.0` are allowed. The
+// exponent form is limited to the absolute values less than 2^65536, so `1e600000000` is the WrongRequest error too.
+func (n *Node) GetBigInt() (value *big.Int, err error) {
+	value, err = n.getBigInt(maxBigIntBits)
+	if err == nil && value == nil {
+		return nil, errorRequest("node is too big for INT")
+	}
+	return value, err
+}
+
+// getBigInt returns the integer value of the node, or nil if the value written in the exponent form doesn't fit into
+// the bits, so the short literal like `1e600000000` doesn't allocate the huge integer
+func (n *Node) getBigInt(bits int) (value *big.Int, err error) {
+	literal, err := n.GetNumberString()
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := new(big.Int).SetString(literal, 0); ok {
+		return value, nil
+	}
+	float, err := n.GetBigFloat()
+	if err != nil {
+		return nil, err
+	}
+	if float.MantExp(nil) > bits {
+		return nil, nil
+	}
+	if !float.IsInt() {
+		return nil, errorRequest("node is not INT")
+	}
+	value, _ = float.Int(nil)
+	return value, nil
+}
+
+// GetBigFloat returns *big.Float with the precision, enough to keep all digits of the source, if current type is
+// Numeric, else: WrongType error. Integers are exact up to 2^65536.
+func (n *Node) GetBigFloat() (value *big.Float, err error) {
+	literal, err := n.GetNumberString()
+	if err != nil {
+		return nil, err
+	}
+	prec := uint(len(literal)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+	value, _, err = big.ParseFloat(literal, 0, prec, big.ToNearestEven)
+	if err == nil {
+		if exp := value.MantExp(nil); exp > int(prec) && exp <= maxBigIntBits {
+			// the exponent form, e.g. `1e40`, needs more bits for the integer part
+			value, _, err = big.ParseFloat(literal, 0, uint(exp), big.ToNearestEven)
+		}
+	}
+	if err != nil {
+		number, ferr := n.GetNumeric()
+		if ferr != nil || math.IsNaN(number) {
+			return nil, errorRequest("node is not a finite number")
+		}
+		return new(big.Float).SetFloat64(number), nil
+	}
+	return value, nil
+}
+
 // GetString returns string, if current type is String, else: WrongType error.
 func (n *Node) GetString() (value string, err error) {
 	if n == nil {
@@ -566,49 +753,10 @@ func (n *Node) MustObject() (value map[string]*Node) {
 }
 
 // Unpack will produce current node to it's interface, recursively with all underlying nodes (in contrast to Node.Value).
+// Objects are unpacked into the map[string]interface{}, so the order of keys is lost: use Node.Keys to get it, or
+// Node.UnpackWithOptions with the OrderedObjects option.
 func (n *Node) Unpack() (value interface{}, err error) {
-	if n == nil {
-		return nil, errorUnparsed()
-	}
-	switch n._type {
-	case Null:
-		return nil, nil
-	case Numeric:
-		value, err = n.Value()
-		if _, ok := value.(float64); !ok {
-			return nil, errorType()
-		}
-	case String:
-		value, err = n.Value()
-		if _, ok := value.(string); !ok {
-			return nil, errorType()
-		}
-	case Bool:
-		value, err = n.Value()
-		if _, ok := value.(bool); !ok {
-			return nil, errorType()
-		}
-	case Array:
-		children := make([]interface{}, len(n.children))
-		for _, child := range n.children {
-			val, err := child.Unpack()
-			if err != nil {
-				return nil, err
-			}
-			children[*child.index] = val
-		}
-		value = children
-	case Object:
-		result := make(map[string]interface{})
-		for key, child := range n.children {
-			result[key], err = child.Unpack()
-			if err != nil {
-				return nil, err
-			}
-		}
-		value = result
-	}
-	return
+	return n.UnpackWithOptions(UnpackOptions{})
 }
 
 // GetIndex will return child node of current array node. If current node is not Array, or index is unavailable, will return error.
@@ -620,9 +768,9 @@ func (n *Node) GetIndex(index int) (*Node, error) {
 		return nil, errorType()
 	}
 	if index < 0 {
-		index += len(n.children)
+		index += n.Size()
 	}
-	child, ok := n.children[strconv.Itoa(index)]
+	child, ok := n.child(strconv.Itoa(index))
 	if !ok {
 		return nil, errorRequest("out of index %d", index)
 	}
@@ -646,7 +794,7 @@ func (n *Node) GetKey(key string) (*Node, error) {
 	if n._type != Object {
 		return nil, errorType()
 	}
-	value, ok := n.children[key]
+	value, ok := n.child(key)
 	if !ok {
 		return nil, errorRequest("wrong key '%s'", key)
 	}
@@ -667,7 +815,7 @@ func (n *Node) HasKey(key string) bool {
 	if n == nil {
 		return false
 	}
-	_, ok := n.children[key]
+	_, ok := n.child(key)
 	return ok
 }
 
@@ -676,7 +824,7 @@ func (n *Node) Empty() bool {
 	if n == nil {
 		return false
 	}
-	return len(n.children) == 0
+	return n.Size() == 0
 }
 
 // Path returns full JsonPath of current Node.
@@ -878,6 +1026,33 @@ func (n *Node) ready() bool {
 	return n.borders[1] != 0
 }
 
+// literal returns the source of the scalar value: parsed, or given with SetNumberString.
+func (n *Node) literal() []byte {
+	if n.data == nil || !n.ready() {
+		return nil
+	}
+	return (*n.data)[n.borders[0]:n.borders[1]]
+}
+
+// setChild stores the child of an Object by the key, new keys are added to the end of the keys order.
+func (n *Node) setChild(key string, child *Node) {
+	if _, ok := n.children[key]; !ok {
+		n.keys = append(n.keys, key)
+	}
+	n.children[key] = child
+}
+
+// deleteChild removes the child of an Object by the key, with the key from the keys order.
+func (n *Node) deleteChild(key string) {
+	delete(n.children, key)
+	for i := len(n.keys) - 1; i >= 0; i-- {
+		if n.keys[i] == key {
+			n.keys = append(n.keys[:i], n.keys[i+1:]...)
+			break
+		}
+	}
+}
+
 func (n *Node) isContainer() bool {
 	return n._type == Array || n._type == Object
 }
@@ -912,7 +1087,7 @@ func (n *Node) Inheritors() (result []*Node) {
 	if n == nil {
 		return nil
 	}
-	size := len(n.children)
+	size := n.Size()
 	if n.IsObject() {
 		result = make([]*Node, size)
 		keys := n.Keys()
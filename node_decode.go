@@ -0,0 +1,470 @@
+package ajson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	nodeType            = reflect.TypeOf(Node{})
+	numberType          = reflect.TypeOf(json.Number(""))
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	structFieldsCache   sync.Map // map[reflect.Type][]structField
+)
+
+// structField is a field of a struct, that can be decoded from or encoded into the object value
+type structField struct {
+	name      string
+	index     []int
+	tagged    bool
+	quoted    bool
+	omitEmpty bool
+}
+
+// Decode stores the value of the current node into the value pointed to by v, in the same way as json.Unmarshal does,
+// but without marshaling the node: structs, maps, slices, arrays, pointers, interfaces and primitives are populated
+// from the node tree directly.
+//
+// Struct fields are matched by the `json:"name,omitempty,string"` tags, or by the names of the fields (case-insensitive).
+// Types, that implements json.Unmarshaler or encoding.TextUnmarshaler are supported as well, fields of the type *Node
+// get a clone of the node.
+//
+// Decode returns TypeMismatch error with the Path of the node, if its value can't be stored into the Go value.
+func (n *Node) Decode(v interface{}) error {
+	if n == nil {
+		return errorUnparsed()
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return unsupportedType(v)
+	}
+	return n.decode(value.Elem(), false)
+}
+
+// decode stores value of the node into v. If quoted is true, scalar value was encoded inside the JSON string.
+func (n *Node) decode(v reflect.Value, quoted bool) (err error) {
+	if quoted {
+		return n.decodeQuoted(v)
+	}
+	if n.IsNull() {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.Type().Elem() == nodeType {
+			v.Set(reflect.ValueOf(n.Clone()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return n.decode(v.Elem(), false)
+	}
+	if v.CanAddr() {
+		if pointer := v.Addr(); pointer.Type().Implements(unmarshalerType) {
+			data, err := Marshal(n)
+			if err != nil {
+				return err
+			}
+			return pointer.Interface().(json.Unmarshaler).UnmarshalJSON(data)
+		} else if n.IsString() && pointer.Type().Implements(textUnmarshalerType) {
+			return pointer.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(n.MustString()))
+		}
+	}
+	if n.IsNull() {
+		// null leaves the value untouched, as json.Unmarshal does
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return n.mismatch(v.Type())
+		}
+		value, err := n.Unpack()
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+	case reflect.Bool:
+		value, err := n.GetBool()
+		if err != nil {
+			return n.mismatch(v.Type())
+		}
+		v.SetBool(value)
+	case reflect.String:
+		if v.Type() == numberType && n.IsNumeric() {
+			value, err := n.GetNumberString()
+			if err != nil {
+				return err
+			}
+			v.SetString(value)
+			return nil
+		}
+		value, err := n.GetString()
+		if err != nil {
+			return n.mismatch(v.Type())
+		}
+		v.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := n.GetInt64()
+		if err != nil || v.OverflowInt(value) {
+			return n.mismatch(v.Type())
+		}
+		v.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, err := n.GetUint64()
+		if err != nil || v.OverflowUint(value) {
+			return n.mismatch(v.Type())
+		}
+		v.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := n.GetNumeric()
+		if err != nil || v.OverflowFloat(value) {
+			return n.mismatch(v.Type())
+		}
+		v.SetFloat(value)
+	case reflect.Slice:
+		return n.decodeSlice(v)
+	case reflect.Array:
+		return n.decodeArray(v)
+	case reflect.Map:
+		return n.decodeMap(v)
+	case reflect.Struct:
+		return n.decodeStruct(v)
+	default:
+		return n.mismatch(v.Type())
+	}
+	return nil
+}
+
+// decodeQuoted decodes scalar value, that was encoded inside the JSON string (the `string` option of the tag)
+func (n *Node) decodeQuoted(v reflect.Value) error {
+	if n.IsNull() {
+		return nil
+	}
+	value, err := n.GetString()
+	if err != nil {
+		return n.mismatch(v.Type())
+	}
+	root, err := Unmarshal([]byte(value))
+	if err != nil || root.isContainer() {
+		return n.mismatch(v.Type())
+	}
+	if err = root.decode(v, false); err != nil {
+		return n.mismatch(v.Type())
+	}
+	return nil
+}
+
+func (n *Node) decodeSlice(v reflect.Value) error {
+	if n.IsString() && v.Type().Elem().Kind() == reflect.Uint8 {
+		value, err := base64.StdEncoding.DecodeString(n.MustString())
+		if err != nil {
+			return n.mismatch(v.Type())
+		}
+		v.SetBytes(value)
+		return nil
+	}
+	if !n.IsArray() {
+		return n.mismatch(v.Type())
+	}
+	children := n.Inheritors()
+	result := reflect.MakeSlice(v.Type(), len(children), len(children))
+	for i, child := range children {
+		if err := child.decode(result.Index(i), false); err != nil {
+			return err
+		}
+	}
+	v.Set(result)
+	return nil
+}
+
+func (n *Node) decodeArray(v reflect.Value) error {
+	if !n.IsArray() {
+		return n.mismatch(v.Type())
+	}
+	children := n.Inheritors()
+	for i := 0; i < v.Len(); i++ {
+		if i < len(children) {
+			if err := children[i].decode(v.Index(i), false); err != nil {
+				return err
+			}
+		} else {
+			v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+		}
+	}
+	return nil
+}
+
+func (n *Node) decodeMap(v reflect.Value) error {
+	if !n.IsObject() {
+		return n.mismatch(v.Type())
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	keyType := v.Type().Key()
+	for _, key := range n.Keys() {
+		child := n.children[key]
+		mkey := reflect.New(keyType).Elem()
+		switch {
+		case reflect.PtrTo(keyType).Implements(textUnmarshalerType):
+			if err := mkey.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+				return child.mismatch(keyType)
+			}
+		case keyType.Kind() == reflect.String:
+			mkey.SetString(key)
+		case keyType.Kind() >= reflect.Int && keyType.Kind() <= reflect.Int64:
+			value, err := strconv.ParseInt(key, 10, 64)
+			if err != nil || mkey.OverflowInt(value) {
+				return child.mismatch(keyType)
+			}
+			mkey.SetInt(value)
+		case keyType.Kind() >= reflect.Uint && keyType.Kind() <= reflect.Uintptr:
+			value, err := strconv.ParseUint(key, 10, 64)
+			if err != nil || mkey.OverflowUint(value) {
+				return child.mismatch(keyType)
+			}
+			mkey.SetUint(value)
+		default:
+			return n.mismatch(v.Type())
+		}
+		value := reflect.New(v.Type().Elem()).Elem()
+		if err := child.decode(value, false); err != nil {
+			return err
+		}
+		v.SetMapIndex(mkey, value)
+	}
+	return nil
+}
+
+func (n *Node) decodeStruct(v reflect.Value) error {
+	if !n.IsObject() {
+		return n.mismatch(v.Type())
+	}
+	fields := structFields(v.Type())
+	for _, key := range n.Keys() {
+		field := findField(fields, key)
+		if field == nil {
+			continue
+		}
+		value, ok := fieldByIndex(v, field.index, true)
+		if !ok {
+			continue
+		}
+		if err := n.children[key].decode(value, field.quoted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mismatch returns TypeMismatch error for the current node
+func (n *Node) mismatch(_type reflect.Type) error {
+	return errorMismatch(n.Path(), fmt.Sprintf("cannot decode %s into %s", typeName(n), _type))
+}
+
+// typeName returns human-readable type of the node
+func typeName(n *Node) string {
+	switch n.Type() {
+	case Null:
+		return "null"
+	case Numeric:
+		return "number " + string(n.Source())
+	case String:
+		return "string"
+	case Bool:
+		return "bool"
+	case Array:
+		return "array"
+	case Object:
+		return "object"
+	}
+	return "unknown"
+}
+
+// findField returns the field by its name, or by the name in any case
+func findField(fields []structField, name string) *structField {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of the struct, nil pointers to the embedded structs are allocated if allocate is true
+func fieldByIndex(v reflect.Value, index []int, allocate bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !allocate || !v.CanSet() {
+					return v, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// structFields returns all fields of the struct, that can be decoded or encoded, including the fields of the embedded
+// structs
+func structFields(_type reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(_type); ok {
+		return fields.([]structField)
+	}
+	fields := dominantFields(collectFields(_type))
+	// fields of the embedded structs are placed in the order of their declaration
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	structFieldsCache.Store(_type, fields)
+	return fields
+}
+
+// collectFields returns all fields of the struct and of the embedded structs, level by level, as encoding/json does.
+// Each embedded struct type is visited once, fields of the type, embedded more than once at the same level, are
+// returned twice, so they hide each other.
+func collectFields(_type reflect.Type) (fields []structField) {
+	type embedded struct {
+		_type reflect.Type
+		index []int
+	}
+	var current []embedded
+	next := []embedded{{_type: _type}}
+	count, nextCount := map[reflect.Type]int{}, map[reflect.Type]int{_type: 1}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+		for _, parent := range current {
+			if visited[parent._type] {
+				continue
+			}
+			visited[parent._type] = true
+			for i := 0; i < parent._type.NumField(); i++ {
+				field := parent._type.Field(i)
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options := tag, ""
+				if comma := strings.IndexByte(tag, ','); comma != -1 {
+					name, options = tag[:comma], tag[comma:]
+				}
+				index := append(append([]int(nil), parent.index...), i)
+				if field.Anonymous && name == "" {
+					fieldType := field.Type
+					if fieldType.Kind() == reflect.Ptr {
+						fieldType = fieldType.Elem()
+					}
+					if fieldType.Kind() == reflect.Struct {
+						nextCount[fieldType]++
+						if nextCount[fieldType] == 1 {
+							next = append(next, embedded{_type: fieldType, index: index})
+						}
+						continue
+					}
+				}
+				if field.PkgPath != "" {
+					// unexported field
+					continue
+				}
+				entry := structField{
+					name:      name,
+					index:     index,
+					tagged:    name != "",
+					quoted:    strings.Contains(options, ",string") && isScalarKind(field.Type),
+					omitEmpty: strings.Contains(options, ",omitempty"),
+				}
+				if name == "" {
+					entry.name = field.Name
+				}
+				fields = append(fields, entry)
+				if count[parent._type] > 1 {
+					fields = append(fields, entry)
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// dominantFields returns the fields, that are not hidden by the other fields with the same name: the shallowest
+// field wins, if there are several of them, the only tagged one wins, otherwise all of them are dropped.
+func dominantFields(fields []structField) []structField {
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		if a.tagged != b.tagged {
+			return a.tagged
+		}
+		return lessIndex(a.index, b.index)
+	})
+	result := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if j-i == 1 || len(fields[i].index) < len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			result = append(result, fields[i])
+		}
+		i = j
+	}
+	return result
+}
+
+// lessIndex compares the indexes of the fields in the order of their declaration
+func lessIndex(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// isScalarKind returns true if the `string` option of the tag can be applied to the type
+func isScalarKind(_type reflect.Type) bool {
+	if _type.Kind() == reflect.Ptr {
+		_type = _type.Elem()
+	}
+	switch _type.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
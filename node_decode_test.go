@@ -0,0 +1,290 @@
+package ajson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleNode_Decode() {
+	type Book struct {
+		Title  string  `json:"title"`
+		Author string  `json:"author"`
+		Price  float64 `json:"price"`
+		ISBN   *string `json:"isbn,omitempty"`
+	}
+	root := Must(Unmarshal([]byte(`{"store": {"book": [
+		{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+		{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99}
+	]}}`)))
+	nodes, err := root.JSONPath("$..book[?(@.isbn)]")
+	if err != nil {
+		panic(err)
+	}
+	var book Book
+	if err = nodes[0].Decode(&book); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s by %s: %v, %s\n", book.Title, book.Author, book.Price, *book.ISBN)
+
+	var books []Book
+	err = root.MustKey("store").Decode(&books)
+	fmt.Println(err)
+	// Output:
+	// Moby Dick by Herman Melville: 8.99, 0-553-21311-3
+	// type mismatch: cannot decode object into []ajson.Book at $['store']
+}
+
+type decodeBase struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+type decodeMeta struct {
+	Name string
+}
+
+type decodeText string
+
+func (t *decodeText) UnmarshalText(text []byte) error {
+	*t = decodeText("text:" + string(text))
+	return nil
+}
+
+type decodeStruct struct {
+	decodeBase
+	*decodeMeta
+	Name     string             `json:"name"`
+	Skip     string             `json:"-"`
+	Quoted   int64              `json:"quoted,string"`
+	QFloat   *float64           `json:"qfloat,string"`
+	QBool    bool               `json:"qbool,string"`
+	QString  string             `json:"qstring,string"`
+	Unsigned uint8              `json:"unsigned"`
+	Big      int64              `json:"big"`
+	Number   json.Number        `json:"number"`
+	Float    float32            `json:"float"`
+	Any      interface{}        `json:"any"`
+	List     []int              `json:"list"`
+	Array    [2]string          `json:"array"`
+	Bytes    []byte             `json:"bytes"`
+	Map      map[string]bool    `json:"map"`
+	IntMap   map[int]string     `json:"int_map"`
+	TextMap  map[decodeText]int `json:"text_map"`
+	Pointer  **int              `json:"pointer"`
+	Nil      *int               `json:"nil"`
+	Kept     int                `json:"kept"`
+	Node     *Node              `json:"node"`
+	Value    Node               `json:"value"`
+	Text     decodeText         `json:"text"`
+	Time     time.Time          `json:"time"`
+	BigInt   *big.Int           `json:"big_int"`
+	Inner    struct {
+		Value []*decodeMeta `json:"value"`
+	} `json:"inner"`
+	hidden string
+}
+
+// decodeSelf embeds itself
+type decodeSelf struct {
+	*decodeSelf
+	A int `json:"a"`
+}
+
+type decodeFirst struct {
+	X int
+	Y int
+}
+
+type decodeSecond struct {
+	X int
+	Z int `json:"Y"`
+}
+
+// decodeConflict has the same fields at the same depth: the tagged Y wins, both X are dropped
+type decodeConflict struct {
+	decodeFirst
+	decodeSecond
+}
+
+type decodeWrapper struct {
+	decodeFirst
+}
+
+// decodeTwice embeds decodeFirst twice at the same depth, so its fields hide each other
+type decodeTwice struct {
+	decodeWrapper
+	decodeConflict
+}
+
+func TestNode_Decode_embedded(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		value func() interface{}
+	}{
+		{name: "self", input: `{"a": 1}`, value: func() interface{} { return new(decodeSelf) }},
+		{name: "conflict", input: `{"X": 1, "Y": 2}`, value: func() interface{} { return new(decodeConflict) }},
+		{name: "twice", input: `{"X": 1, "Y": 2}`, value: func() interface{} { return new(decodeTwice) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, expected := test.value(), test.value()
+			if err := json.Unmarshal([]byte(test.input), expected); err != nil {
+				t.Fatalf("json.Unmarshal() unexpected error: %s", err)
+			}
+			if err := Must(Unmarshal([]byte(test.input))).Decode(result); err != nil {
+				t.Fatalf("Decode() unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Decode() wrong result: %#v, expected %#v", result, expected)
+			}
+		})
+	}
+}
+
+func TestNode_Decode(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{
+		"id": 1, "created": "today", "name": "name", "skip": "skip", "hidden": "hidden",
+		"quoted": "123", "qfloat": "1.5", "qbool": "true", "qstring": "\"str\"",
+		"unsigned": 255, "big": 9007199254740993, "number": 1.50e1, "float": 0.5,
+		"any": {"a": [1, "b", null, true]}, "list": [1, 2, 3], "array": ["a", "b", "c"],
+		"bytes": "AQID", "map": {"x": true, "y": false}, "int_map": {"1": "one", "-2": "minus two"},
+		"text_map": {"a": 1}, "pointer": 5, "nil": null, "kept": null,
+		"node": {"a": [1, 2]}, "value": [true], "text": "value",
+		"time": "2020-01-02T03:04:05Z", "big_int": 123456789012345678901234567890,
+		"inner": {"value": [{"name": "first"}, null]}
+	}`)))
+	value := decodeStruct{Kept: 42, Skip: "kept"}
+	if err := root.Decode(&value); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	pointer := 5
+	ppointer := &pointer
+	qfloat := 1.5
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	expected := decodeStruct{
+		decodeBase: decodeBase{ID: 1, Created: "today"},
+		Name:       "name",
+		Skip:       "kept",
+		Quoted:     123,
+		QFloat:     &qfloat,
+		QBool:      true,
+		QString:    "str",
+		Unsigned:   255,
+		Big:        9007199254740993,
+		Number:     "1.50e1",
+		Float:      0.5,
+		Any:        map[string]interface{}{"a": []interface{}{1.0, "b", nil, true}},
+		List:       []int{1, 2, 3},
+		Array:      [2]string{"a", "b"},
+		Bytes:      []byte{1, 2, 3},
+		Map:        map[string]bool{"x": true, "y": false},
+		IntMap:     map[int]string{1: "one", -2: "minus two"},
+		TextMap:    map[decodeText]int{"text:a": 1},
+		Pointer:    &ppointer,
+		Kept:       42,
+		Text:       "text:value",
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		BigInt:     bigInt,
+	}
+	expected.Inner.Value = []*decodeMeta{{Name: "first"}, nil}
+
+	node, valueNode := value.Node, value.Value
+	value.Node, value.Value = nil, Node{}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("Decode() wrong result:\n%#v\n%#v", value, expected)
+	}
+	if node.String() != `{"a": [1, 2]}` || node.Parent() != nil || node == root.MustKey("node") {
+		t.Errorf("Decode() wrong node: %s", node)
+	}
+	if valueNode.String() != `[true]` {
+		t.Errorf("Decode() wrong value node: %s", valueNode.String())
+	}
+}
+
+func TestNode_Decode_types(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		value    interface{}
+		expected interface{}
+	}{
+		{name: "int", input: `-12`, value: new(int), expected: -12},
+		{name: "int exponent", input: `1e3`, value: new(int), expected: 1000},
+		{name: "uint64", input: `18446744073709551615`, value: new(uint64), expected: uint64(18446744073709551615)},
+		{name: "float", input: `1.5e-3`, value: new(float64), expected: 1.5e-3},
+		{name: "string", input: `"a\nb"`, value: new(string), expected: "a\nb"},
+		{name: "bool", input: `true`, value: new(bool), expected: true},
+		{name: "interface", input: `[1, {"a": null}]`, value: new(interface{}), expected: []interface{}{1.0, map[string]interface{}{"a": nil}}},
+		{name: "null interface", input: `null`, value: new(interface{}), expected: nil},
+		{name: "null slice", input: `null`, value: &[]int{1}, expected: []int(nil)},
+		{name: "null int", input: `null`, value: func() *int { i := 7; return &i }(), expected: 7},
+		{name: "map of slices", input: `{"a": [1], "b": []}`, value: new(map[string][]int), expected: map[string][]int{"a": {1}, "b": {}}},
+		{name: "case insensitive", input: `{"NAME": "a"}`, value: new(decodeMeta), expected: decodeMeta{Name: "a"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Must(Unmarshal([]byte(test.input))).Decode(test.value); err != nil {
+				t.Fatalf("Decode() unexpected error: %s", err)
+			}
+			if result := reflect.ValueOf(test.value).Elem().Interface(); !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Decode() wrong result: %#v", result)
+			}
+		})
+	}
+}
+
+func TestNode_Decode_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		value   interface{}
+		message string
+	}{
+		{name: "string into int", input: `{"id": "1"}`, value: new(decodeBase), message: "type mismatch: cannot decode string into int at $['id']"},
+		{name: "float into int", input: `[{"a": [1.5]}]`, value: new([]map[string][]int), message: "type mismatch: cannot decode number 1.5 into int at $[0]['a'][0]"},
+		{name: "overflow", input: `[256]`, value: new([]uint8), message: "type mismatch: cannot decode number 256 into uint8 at $[0]"},
+		{name: "negative", input: `-1`, value: new(uint), message: "type mismatch: cannot decode number -1 into uint at $"},
+		{name: "float32 overflow", input: `1e300`, value: new(float32), message: "type mismatch: cannot decode number 1e300 into float32 at $"},
+		{name: "object into slice", input: `{}`, value: new([]int), message: "type mismatch: cannot decode object into []int at $"},
+		{name: "array into struct", input: `{"inner": []}`, value: new(decodeStruct), message: "type mismatch: cannot decode array into struct { Value []*ajson.decodeMeta \"json:\\\"value\\\"\" } at $['inner']"},
+		{name: "wrong base64", input: `"!"`, value: new([]byte), message: "type mismatch: cannot decode string into []uint8 at $"},
+		{name: "wrong quoted", input: `{"quoted": "abc"}`, value: new(decodeStruct), message: "type mismatch: cannot decode string into int64 at $['quoted']"},
+		{name: "not quoted", input: `{"quoted": 1}`, value: new(decodeStruct), message: "type mismatch: cannot decode number 1 into int64 at $['quoted']"},
+		{name: "wrong map key", input: `{"a": "b"}`, value: new(map[int]string), message: "type mismatch: cannot decode string into int at $['a']"},
+		{name: "unsupported map key", input: `{"a": "b"}`, value: new(map[float64]string), message: "type mismatch: cannot decode object into map[float64]string at $"},
+		{name: "non-empty interface", input: `1`, value: new(fmt.Stringer), message: "type mismatch: cannot decode number 1 into fmt.Stringer at $"},
+		{name: "channel", input: `1`, value: new(chan int), message: "type mismatch: cannot decode number 1 into chan int at $"},
+		{name: "unmarshaler", input: `{"time": "yesterday"}`, value: new(decodeStruct), message: `parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Must(Unmarshal([]byte(test.input))).Decode(test.value)
+			if err == nil {
+				t.Fatalf("Decode() expected error")
+			}
+			if err.Error() != test.message {
+				t.Errorf("Decode() wrong error:\n%s\n%s", err, test.message)
+			}
+		})
+	}
+
+	var jerr Error
+	if err := Must(Unmarshal([]byte(`[true]`))).Decode(new([]string)); !errors.As(err, &jerr) || jerr.Type != TypeMismatch || jerr.Value != "$[0]" {
+		t.Errorf("Decode() wrong error: %#v", err)
+	}
+	if err := Must(Unmarshal([]byte(`1`))).Decode(nil); err == nil {
+		t.Errorf("Decode() expected error for nil")
+	}
+	if err := Must(Unmarshal([]byte(`1`))).Decode(1); err == nil {
+		t.Errorf("Decode() expected error for non-pointer")
+	}
+	if err := (*Node)(nil).Decode(new(int)); err == nil {
+		t.Errorf("Decode() expected error for nil node")
+	}
+}
@@ -0,0 +1,274 @@
+package ajson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// visit is the reference of the pointer, map or slice, that is being encoded, to detect cycles
+type visit struct {
+	pointer uintptr
+	_type   reflect.Type
+	size    int
+}
+
+// FromValue returns the root node built from the Go value, in the same way as json.Marshal encodes it: structs
+// become objects (fields are matched by the `json:"name,omitempty,string"` tags), maps with string, integer or
+// encoding.TextMarshaler keys become objects with sorted keys, slices and arrays become arrays, []byte becomes the
+// base64 string and pointers and interfaces are resolved to their values.
+//
+// Types, that implements json.Marshaler or encoding.TextMarshaler are supported as well, values of the type *Node
+// are cloned. Integers are stored exactly as they are, without conversion to float64.
+func FromValue(v interface{}) (*Node, error) {
+	return fromValue(reflect.ValueOf(v), make(map[visit]struct{}))
+}
+
+// fromValue creates the node from the value, visiting contains containers on the current branch
+func fromValue(v reflect.Value, visiting map[visit]struct{}) (node *Node, err error) {
+	if !v.IsValid() {
+		return NullNode(""), nil
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return NullNode(""), nil
+	}
+	if v.Type() == nodeType {
+		if !v.CanAddr() {
+			pointer := reflect.New(nodeType)
+			pointer.Elem().Set(v)
+			v = pointer.Elem()
+		}
+		return v.Addr().Interface().(*Node).Clone(), nil
+	}
+	if v.Kind() == reflect.Ptr && v.Type().Elem() == nodeType {
+		return v.Interface().(*Node).Clone(), nil
+	}
+	if marshaler, ok := asInterface(v, marshalerType).(json.Marshaler); ok {
+		data, err := marshaler.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return Unmarshal(data)
+	}
+	if marshaler, ok := asInterface(v, textMarshalerType).(encoding.TextMarshaler); ok {
+		data, err := marshaler.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return StringNode("", string(data)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return BoolNode("", v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return numberNode(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return numberNode(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		value := v.Float()
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errorRequest("unsupported number %v", value)
+		}
+		if v.Kind() == reflect.Float32 {
+			// keep the shortest representation of float32, instead of the float64 one: 0.1 instead of 0.10000000149011612
+			value, _ = strconv.ParseFloat(strconv.FormatFloat(value, 'g', -1, 32), 64)
+		}
+		return NumericNode("", value), nil
+	case reflect.String:
+		if v.Type() == numberType {
+			value := v.String()
+			if value == "" {
+				value = "0"
+			}
+			return numberNode(value)
+		}
+		return StringNode("", v.String()), nil
+	case reflect.Interface, reflect.Ptr:
+		if v.Kind() == reflect.Ptr {
+			if err = enter(v, visiting); err != nil {
+				return nil, err
+			}
+			defer leave(v, visiting)
+		}
+		return fromValue(v.Elem(), visiting)
+	case reflect.Map:
+		return fromMap(v, visiting)
+	case reflect.Slice:
+		if v.IsNil() {
+			return NullNode(""), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && !isMarshaler(v.Type().Elem()) {
+			return StringNode("", base64.StdEncoding.EncodeToString(v.Bytes())), nil
+		}
+		if err = enter(v, visiting); err != nil {
+			return nil, err
+		}
+		defer leave(v, visiting)
+		return fromArray(v, visiting)
+	case reflect.Array:
+		return fromArray(v, visiting)
+	case reflect.Struct:
+		return fromStruct(v, visiting)
+	}
+	return nil, unsupportedType(v.Interface())
+}
+
+// numberNode creates the Numeric node, which will be marshaled exactly as the given string
+func numberNode(value string) (*Node, error) {
+	node := NullNode("")
+	if err := node.SetNumberString(value); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func fromArray(v reflect.Value, visiting map[visit]struct{}) (*Node, error) {
+	children := make([]*Node, v.Len())
+	for i := range children {
+		child, err := fromValue(v.Index(i), visiting)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = child
+	}
+	return ArrayNode("", children), nil
+}
+
+func fromMap(v reflect.Value, visiting map[visit]struct{}) (*Node, error) {
+	if v.IsNil() {
+		return NullNode(""), nil
+	}
+	if err := enter(v, visiting); err != nil {
+		return nil, err
+	}
+	defer leave(v, visiting)
+
+	children := make(map[string]*Node, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		child, err := fromValue(iter.Value(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		children[key] = child
+	}
+	return ObjectNode("", children), nil
+}
+
+func fromStruct(v reflect.Value, visiting map[visit]struct{}) (*Node, error) {
+	result := ObjectNode("", make(map[string]*Node))
+	for _, field := range structFields(v.Type()) {
+		value, ok := fieldByIndex(v, field.index, false)
+		if !ok || (field.omitEmpty && isEmptyValue(value)) {
+			continue
+		}
+		child, err := fromValue(value, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if field.quoted && !child.IsNull() {
+			data, err := Marshal(child)
+			if err != nil {
+				return nil, err
+			}
+			child = StringNode("", string(data))
+		}
+		if err = result.AppendObject(field.name, child); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// mapKey returns the key of the map as the string
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if marshaler, ok := asInterface(key, textMarshalerType).(encoding.TextMarshaler); ok {
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return "", nil
+		}
+		data, err := marshaler.MarshalText()
+		return string(data), err
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", unsupportedType(key.Interface())
+}
+
+// asInterface returns the value, or the pointer to the addressable value, if it implements the interface
+func asInterface(v reflect.Value, _interface reflect.Type) interface{} {
+	if v.Type().Implements(_interface) {
+		return v.Interface()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(_interface) {
+		return v.Addr().Interface()
+	}
+	return nil
+}
+
+// isMarshaler returns true if the type or the pointer to it implements json.Marshaler or encoding.TextMarshaler
+func isMarshaler(_type reflect.Type) bool {
+	pointer := reflect.PtrTo(_type)
+	return _type.Implements(marshalerType) || pointer.Implements(marshalerType) ||
+		_type.Implements(textMarshalerType) || pointer.Implements(textMarshalerType)
+}
+
+// isEmptyValue returns true for the values, that are omitted with the `omitempty` option of the tag
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// enter marks the container as being encoded, or returns an error if it is already on the current branch
+func enter(v reflect.Value, visiting map[visit]struct{}) error {
+	key := visit{pointer: v.Pointer(), _type: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.size = v.Len()
+	}
+	if _, ok := visiting[key]; ok {
+		return errorRequest("encountered a cycle via %s", v.Type())
+	}
+	visiting[key] = struct{}{}
+	return nil
+}
+
+// leave removes the container from the current branch
+func leave(v reflect.Value, visiting map[visit]struct{}) {
+	key := visit{pointer: v.Pointer(), _type: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.size = v.Len()
+	}
+	delete(visiting, key)
+}
@@ -0,0 +1,265 @@
+package ajson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleFromValue() {
+	type Book struct {
+		Title  string   `json:"title"`
+		Author string   `json:"author"`
+		Price  float64  `json:"price"`
+		ISBN   string   `json:"isbn,omitempty"`
+		Tags   []string `json:"tags"`
+	}
+	root, err := FromValue(map[string]interface{}{
+		"book":  []Book{{Title: "Moby Dick", Author: "Herman Melville", Price: 8.99, Tags: []string{"fiction"}}},
+		"total": uint64(18446744073709551615),
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(root)
+
+	book := root.MustKey("book").MustIndex(0)
+	if err = book.MustKey("tags").Set([]string{"fiction", "classic"}); err != nil {
+		panic(err)
+	}
+	fmt.Println(book)
+	// Output:
+	// {"book":[{"title":"Moby Dick","author":"Herman Melville","price":8.99,"tags":["fiction"]}],"total":18446744073709551615}
+	// {"title":"Moby Dick","author":"Herman Melville","price":8.99,"tags":["fiction","classic"]}
+}
+
+type encodeText struct {
+	value string
+}
+
+func (t encodeText) MarshalText() ([]byte, error) {
+	if t.value == "" {
+		return nil, errors.New("empty text")
+	}
+	return []byte("text:" + t.value), nil
+}
+
+type encodeJSON struct {
+	value string
+}
+
+func (j *encodeJSON) MarshalJSON() ([]byte, error) {
+	if j.value == "" {
+		return []byte(`{`), nil
+	}
+	return []byte(`{"json": "` + j.value + `"}`), nil
+}
+
+type encodeStruct struct {
+	decodeBase
+	*decodeMeta
+	Name    string                `json:"name"`
+	Skip    string                `json:"-"`
+	Dash    string                `json:"-,"`
+	Quoted  int64                 `json:"quoted,string"`
+	QNil    *float64              `json:"qnil,string"`
+	QString string                `json:"qstring,string"`
+	Omit    []int                 `json:"omit,omitempty"`
+	OmitPtr *int                  `json:"omit_ptr,omitempty"`
+	Kept    int                   `json:"kept,omitempty"`
+	Float32 float32               `json:"float32"`
+	Number  json.Number           `json:"number"`
+	Bytes   []byte                `json:"bytes"`
+	Array   [2]bool               `json:"array"`
+	NilMap  map[string]int        `json:"nil_map"`
+	IntMap  map[int8]string       `json:"int_map"`
+	TextMap map[encodeText]string `json:"text_map"`
+	Any     interface{}           `json:"any"`
+	Node    *Node                 `json:"node"`
+	Value   Node                  `json:"value"`
+	Text    encodeText            `json:"text"`
+	JSON    encodeJSON            `json:"json"`
+	Time    time.Time             `json:"time"`
+	IP      net.IP                `json:"ip"`
+	BigInt  *big.Int              `json:"big_int"`
+	hidden  string
+}
+
+func TestFromValue(t *testing.T) {
+	value := &encodeStruct{
+		decodeBase: decodeBase{ID: 1, Created: "today"},
+		Name:       "name",
+		Skip:       "skip",
+		Dash:       "dash",
+		Quoted:     -5,
+		QString:    "str",
+		Kept:       1,
+		Float32:    0.1,
+		Bytes:      []byte{1, 2, 3},
+		Array:      [2]bool{true, false},
+		IntMap:     map[int8]string{-1: "minus", 2: "two"},
+		TextMap:    map[encodeText]string{{value: "b"}: "b", {value: "a"}: "a"},
+		Any:        []interface{}{1, "x", nil, map[string]interface{}{"b": false, "a": 1.5}},
+		Node:       Must(Unmarshal([]byte(`{"a": [1, 2]}`))),
+		Value:      *Must(Unmarshal([]byte(`[true]`))),
+		Text:       encodeText{value: "value"},
+		JSON:       encodeJSON{value: "value"},
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		IP:         net.IPv4(127, 0, 0, 1),
+		BigInt:     new(big.Int).Lsh(big.NewInt(1), 70),
+		hidden:     "hidden",
+	}
+	root, err := FromValue(value)
+	if err != nil {
+		t.Fatalf("FromValue() unexpected error: %s", err)
+	}
+	expected := `{"id":1,"created":"today","name":"name","-":"dash","quoted":"-5","qnil":null,"qstring":"\"str\"",` +
+		`"kept":1,"float32":0.1,"number":0,"bytes":"AQID","array":[true,false],"nil_map":null,` +
+		`"int_map":{"-1":"minus","2":"two"},"text_map":{"text:a":"a","text:b":"b"},"any":[1,"x",null,{"a":1.5,"b":false}],` +
+		`"node":{"a": [1, 2]},"value":[true],"text":"text:value","json":{"json": "value"},"time":"2020-01-02T03:04:05Z",` +
+		`"ip":"127.0.0.1","big_int":1180591620717411303424}`
+	if root.String() != expected {
+		t.Errorf("FromValue() wrong result:\n%s\n%s", root.String(), expected)
+	}
+	if data, _ := json.Marshal(value); !bytes.Equal(mustBytes(MarshalCanonical(Must(Unmarshal(data)))), mustBytes(MarshalCanonical(root))) {
+		t.Errorf("FromValue() differs from json.Marshal(): %s", data)
+	}
+	if root.MustKey("node") == value.Node || root.MustKey("node").Parent() != root {
+		t.Errorf("FromValue() node was not cloned")
+	}
+
+	var decoded struct {
+		decodeBase
+		Name   string          `json:"name"`
+		Quoted int64           `json:"quoted,string"`
+		IntMap map[int8]string `json:"int_map"`
+		Time   time.Time       `json:"time"`
+		BigInt *big.Int        `json:"big_int"`
+	}
+	if err = root.Decode(&decoded); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if decoded.decodeBase != value.decodeBase || decoded.Name != value.Name || decoded.Quoted != value.Quoted ||
+		!reflect.DeepEqual(decoded.IntMap, value.IntMap) || !decoded.Time.Equal(value.Time) || decoded.BigInt.Cmp(value.BigInt) != 0 {
+		t.Errorf("Decode() wrong result: %#v", decoded)
+	}
+}
+
+func TestFromValue_types(t *testing.T) {
+	number := 42
+	pointer := &number
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "nil", value: nil, expected: `null`},
+		{name: "nil pointer", value: (*int)(nil), expected: `null`},
+		{name: "nil slice", value: []int(nil), expected: `null`},
+		{name: "empty slice", value: []int{}, expected: `[]`},
+		{name: "pointer to pointer", value: &pointer, expected: `42`},
+		{name: "int64", value: int64(math.MinInt64), expected: `-9223372036854775808`},
+		{name: "float", value: 1e21, expected: `1e+21`},
+		{name: "string", value: "<a>\n", expected: `"\u003ca\u003e\n"`},
+		{name: "number", value: json.Number("1.50"), expected: `1.50`},
+		{name: "bool", value: true, expected: `true`},
+		{name: "map", value: map[string]int{"b": 1, "a": 2}, expected: `{"a":2,"b":1}`},
+		{name: "uint map", value: map[uint]bool{10: true, 9: false}, expected: `{"10":true,"9":false}`},
+		{name: "node", value: Must(Unmarshal([]byte(` [1, 2] `))).MustIndex(1), expected: `2`},
+		{name: "nodes", value: []*Node{NullNode("a"), nil}, expected: `[null,null]`},
+		{name: "json marshaler", value: &encodeJSON{value: "x"}, expected: `{"json": "x"}`},
+		{name: "shared pointers", value: []*int{pointer, pointer}, expected: `[42,42]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := FromValue(test.value)
+			if err != nil {
+				t.Fatalf("FromValue() unexpected error: %s", err)
+			}
+			if root.String() != test.expected {
+				t.Errorf("FromValue() wrong result: %s", root.String())
+			}
+			if root.Parent() != nil || root.Key() != "" {
+				t.Errorf("FromValue() wrong reference")
+			}
+		})
+	}
+}
+
+func TestFromValue_embedded(t *testing.T) {
+	self := &decodeSelf{A: 1}
+	self.decodeSelf = self
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "self", value: decodeSelf{A: 1}, expected: `{"a":1}`},
+		{name: "self pointer", value: self, expected: `{"a":1}`},
+		{name: "conflict", value: decodeConflict{decodeFirst{X: 1, Y: 2}, decodeSecond{X: 3, Z: 4}}, expected: `{"Y":4}`},
+		{name: "twice", value: decodeTwice{}, expected: `{"Y":0}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected, err := json.Marshal(test.value)
+			if err != nil {
+				t.Fatalf("json.Marshal() unexpected error: %s", err)
+			}
+			if string(expected) != test.expected {
+				t.Fatalf("json.Marshal() wrong result: %s", expected)
+			}
+			root, err := FromValue(test.value)
+			if err != nil {
+				t.Fatalf("FromValue() unexpected error: %s", err)
+			}
+			if root.String() != test.expected {
+				t.Errorf("FromValue() wrong result: %s", root)
+			}
+		})
+	}
+}
+
+func TestFromValue_errors(t *testing.T) {
+	type cycle struct {
+		Next *cycle `json:"next"`
+	}
+	recursive := &cycle{}
+	recursive.Next = recursive
+	recursiveMap := map[string]interface{}{}
+	recursiveMap["self"] = recursiveMap
+	recursiveSlice := []interface{}{nil}
+	recursiveSlice[0] = recursiveSlice
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "channel", value: make(chan int)},
+		{name: "func field", value: struct{ F func() }{F: func() {}}},
+		{name: "complex", value: []complex64{1}},
+		{name: "NaN", value: math.NaN()},
+		{name: "Inf", value: map[string]float32{"a": float32(math.Inf(1))}},
+		{name: "wrong number", value: json.Number("1.")},
+		{name: "map key", value: map[float64]int{1: 1}},
+		{name: "text marshaler", value: []encodeText{{}}},
+		{name: "text marshaler key", value: map[encodeText]int{{}: 1}},
+		{name: "json marshaler", value: &encodeJSON{}},
+		{name: "cycle", value: recursive},
+		{name: "cycle map", value: recursiveMap},
+		{name: "cycle slice", value: recursiveSlice},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if root, err := FromValue(test.value); err == nil {
+				t.Errorf("FromValue() expected error, got: %s", root)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package ajson
 
 import (
+	"errors"
+	"reflect"
+	"sort"
 	"strconv"
 	"sync/atomic"
 )
@@ -10,18 +13,24 @@ func (n *Node) IsDirty() bool {
 	return n.dirty
 }
 
-// Set updates current node value with the value of any type
+// Set updates current node value with the value of any type: values other than scalars, []*Node, map[string]*Node
+// and *Node are converted with FromValue.
 func (n *Node) Set(value interface{}) error {
 	if value == nil {
 		return n.SetNull()
 	}
 	switch result := value.(type) {
-	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+	case float64, float32:
 		if tValue, err := numeric2float64(value); err != nil {
 			return err
 		} else {
 			return n.SetNumeric(tValue)
 		}
+	case int, int8, int16, int32, int64:
+		// integers are kept exactly, as FromValue does
+		return n.SetInt64(reflect.ValueOf(result).Int())
+	case uint, uint8, uint16, uint32, uint64:
+		return n.SetNumberString(strconv.FormatUint(reflect.ValueOf(result).Uint(), 10))
 	case string:
 		return n.SetString(result)
 	case bool:
@@ -33,7 +42,14 @@ func (n *Node) Set(value interface{}) error {
 	case *Node:
 		return n.SetNode(result)
 	default:
-		return unsupportedType(value)
+		if n == nil {
+			return errorUnparsed()
+		}
+		node, err := FromValue(value)
+		if err != nil {
+			return err
+		}
+		return n.replace(node)
 	}
 }
 
@@ -47,6 +63,30 @@ func (n *Node) SetNumeric(value float64) error {
 	return n.update(Numeric, value)
 }
 
+// SetInt64 updates current node value with Numeric value, which will be marshaled exactly as the given integer
+func (n *Node) SetInt64(value int64) error {
+	return n.SetNumberString(strconv.FormatInt(value, 10))
+}
+
+// SetNumberString updates current node value with Numeric value, which will be marshaled exactly as the given string.
+// The string should be a valid JSON number, e.g. "12345678901234567890" or "-1.5e300".
+func (n *Node) SetNumberString(value string) error {
+	data := []byte(value)
+	if !isNumber(data) {
+		return errorRequest("wrong numeric value '%s'", value)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return err
+	}
+	if err = n.update(Numeric, number); err != nil {
+		return err
+	}
+	n.data = &data
+	n.borders = [2]int{0, len(data)}
+	return nil
+}
+
 // SetString updates current node value with String value
 func (n *Node) SetString(value string) error {
 	return n.update(String, value)
@@ -78,14 +118,7 @@ func (n *Node) SetNode(value *Node) error {
 		return errorRequest("attempt to create infinite loop")
 	}
 
-	node := value.Clone()
-	node.setReference(n.parent, n.key, n.index)
-	n.setReference(nil, nil, nil)
-	*n = *node
-	if n.parent != nil {
-		n.parent.mark()
-	}
-	return nil
+	return n.replace(value.Clone())
 }
 
 // AppendArray appends current Array node values with Node values
@@ -172,9 +205,11 @@ func (n *Node) Clone() *Node {
 }
 
 func (n *Node) clone() *Node {
+	n.load()
 	node := &Node{
 		parent:   n.parent,
 		children: make(map[string]*Node, len(n.children)),
+		keys:     append([]string(nil), n.keys...),
 		key:      cptrs(n.key),
 		index:    cptri(n.index),
 		_type:    n._type,
@@ -182,6 +217,11 @@ func (n *Node) clone() *Node {
 		borders:  n.borders,
 		value:    n.value,
 		dirty:    n.dirty,
+		lenient:  n.lenient,
+	}
+	if node.isContainer() {
+		// cached value of the container refers to the original children
+		node.value = atomic.Value{}
 	}
 	for key, value := range n.children {
 		clone := value.clone()
@@ -191,6 +231,20 @@ func (n *Node) clone() *Node {
 	return node
 }
 
+// replace method replaces current node with the detached node, keeping the position in the parent
+func (n *Node) replace(node *Node) error {
+	node.setReference(n.parent, n.key, n.index)
+	n.setReference(nil, nil, nil)
+	*n = *node
+	for _, child := range n.children {
+		child.parent = n
+	}
+	if n.parent != nil {
+		n.parent.mark()
+	}
+	return nil
+}
+
 // update method updates stored value, with validations
 func (n *Node) update(_type NodeType, value interface{}) error {
 	// validate
@@ -204,6 +258,7 @@ func (n *Node) update(_type NodeType, value interface{}) error {
 
 	atomic.StoreInt32((*int32)(&n._type), int32(_type))
 	n.value = atomic.Value{}
+	n.lenient = false
 	if value != nil {
 		switch _type {
 		case Array:
@@ -218,10 +273,14 @@ func (n *Node) update(_type NodeType, value interface{}) error {
 		case Object:
 			nodes := value.(map[string]*Node)
 			n.children = make(map[string]*Node, len(nodes))
-			for key, node := range nodes {
+			keys := make([]string, 0, len(nodes))
+			for key := range nodes {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
 				tkey := key
-				tnode := node
-				if err = n.appendNode(&tkey, tnode); err != nil {
+				if err = n.appendNode(&tkey, nodes[key]); err != nil {
 					return err
 				}
 			}
@@ -278,11 +337,12 @@ func (n *Node) remove(value *Node) error {
 		return errorRequest("wrong parent")
 	}
 	n.mark()
+	n.value = atomic.Value{}
 	if n.IsArray() {
 		delete(n.children, strconv.Itoa(*value.index))
 		n.dropindex(*value.index)
 	} else {
-		delete(n.children, *value.key)
+		n.deleteChild(*value.key)
 	}
 	value.parent = nil
 	return nil
@@ -305,6 +365,7 @@ func (n *Node) appendNode(key *string, value *Node) error {
 	if n.isParentOrSelfNode(value) {
 		return errorRequest("attempt to create infinite loop")
 	}
+	n.load()
 	if value.parent != nil {
 		if err := value.parent.remove(value); err != nil {
 			return err
@@ -312,15 +373,14 @@ func (n *Node) appendNode(key *string, value *Node) error {
 	}
 	value.parent = n
 	value.key = key
+	// cached value of the container is outdated
+	n.value = atomic.Value{}
 	if key != nil {
-		if old, ok := n.children[*key]; ok {
-			if old != value {
-				if err := n.remove(old); err != nil {
-					return err
-				}
-			}
+		if old, ok := n.children[*key]; ok && old != value {
+			// replaced value keeps the position of the key
+			old.parent = nil
 		}
-		n.children[*key] = value
+		n.setChild(*key, value)
 	} else {
 		index := len(n.children)
 		value.index = &index
@@ -329,6 +389,29 @@ func (n *Node) appendNode(key *string, value *Node) error {
 	return nil
 }
 
+// insertNode inserts value into current Array node at the index, with reindexing of the following values
+func (n *Node) insertNode(index int, value *Node) error {
+	if !n.IsArray() {
+		return errorType()
+	}
+	if index < 0 || index > n.Size() {
+		return errorRequest("out of index %d", index)
+	}
+	if err := n.appendNode(nil, value); err != nil {
+		return err
+	}
+	for i := len(n.children) - 1; i > index; i-- {
+		current := i
+		previous := n.children[strconv.Itoa(i-1)]
+		previous.index = &current
+		n.children[strconv.Itoa(i)] = previous
+	}
+	value.index = &index
+	n.children[strconv.Itoa(index)] = value
+	n.mark()
+	return nil
+}
+
 // mark node as dirty, with all parents (up the tree)
 func (n *Node) mark() {
 	node := n
@@ -346,6 +429,8 @@ func (n *Node) clear() {
 		n.children[key].parent = nil
 	}
 	n.children = nil
+	n.keys = nil
+	n.loader = nil
 }
 
 // isParentOrSelfNode check if current node is the same as given one of parents
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"math"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -426,6 +428,112 @@ func TestNode_MustObject(t *testing.T) {
 	}
 }
 
+func TestNode_GetInt64(t *testing.T) {
+	tests := []struct {
+		name   string
+		node   *Node
+		int64  int64
+		uint64 uint64
+		big    string
+		float  string
+		err    [3]bool
+	}{
+		{name: "zero", node: Must(Unmarshal([]byte(`0`))), int64: 0, uint64: 0, big: "0", float: "0"},
+		{name: "snowflake", node: Must(Unmarshal([]byte(`1234567890123456789`))), int64: 1234567890123456789, uint64: 1234567890123456789, big: "1234567890123456789", float: "1234567890123456789"},
+		{name: "negative", node: Must(Unmarshal([]byte(`-9223372036854775808`))), int64: math.MinInt64, big: "-9223372036854775808", float: "-9223372036854775808", err: [3]bool{false, true, false}},
+		{name: "max uint64", node: Must(Unmarshal([]byte(`18446744073709551615`))), uint64: math.MaxUint64, big: "18446744073709551615", float: "18446744073709551615", err: [3]bool{true, false, false}},
+		{name: "exponent", node: Must(Unmarshal([]byte(`1.5e3`))), int64: 1500, uint64: 1500, big: "1500", float: "1500"},
+		{name: "huge", node: Must(Unmarshal([]byte(`123456789012345678901234567890`))), big: "123456789012345678901234567890", float: "123456789012345678901234567890", err: [3]bool{true, true, false}},
+		{name: "fraction", node: Must(Unmarshal([]byte(`0.5`))), float: "0.5", err: [3]bool{true, true, true}},
+		{name: "NumericNode", node: NumericNode("", 1e15), int64: 1e15, uint64: 1e15, big: "1000000000000000", float: "1000000000000000"},
+		{name: "hexadecimal", node: Must(UnmarshalJSON5([]byte(`0x1F`))), int64: 31, uint64: 31, big: "31", float: "31"},
+		{name: "big exponent", node: Must(Unmarshal([]byte(`1e40`))), big: "1" + strings.Repeat("0", 40), float: "1" + strings.Repeat("0", 40), err: [3]bool{true, true, false}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			i64, err := test.node.GetInt64()
+			if (err != nil) != test.err[0] {
+				t.Errorf("GetInt64() unexpected error: %v", err)
+			} else if err == nil && i64 != test.int64 {
+				t.Errorf("GetInt64() wrong value: %d", i64)
+			}
+			u64, err := test.node.GetUint64()
+			if (err != nil) != test.err[1] {
+				t.Errorf("GetUint64() unexpected error: %v", err)
+			} else if err == nil && u64 != test.uint64 {
+				t.Errorf("GetUint64() wrong value: %d", u64)
+			}
+			integer, err := test.node.GetBigInt()
+			if (err != nil) != test.err[2] {
+				t.Errorf("GetBigInt() unexpected error: %v", err)
+			} else if err == nil && integer.String() != test.big {
+				t.Errorf("GetBigInt() wrong value: %s", integer)
+			}
+			float, err := test.node.GetBigFloat()
+			if err != nil {
+				t.Errorf("GetBigFloat() unexpected error: %v", err)
+			} else if float.Text('f', -1) != test.float {
+				t.Errorf("GetBigFloat() wrong value: %s", float.Text('f', -1))
+			}
+		})
+	}
+}
+
+func TestNode_GetInt64_exponent(t *testing.T) {
+	node := Must(Unmarshal([]byte(`1e600000000`)))
+	for name, getter := range map[string]func() error{
+		"GetInt64":  func() (err error) { _, err = node.GetInt64(); return },
+		"GetUint64": func() (err error) { _, err = node.GetUint64(); return },
+		"GetBigInt": func() (err error) { _, err = node.GetBigInt(); return },
+		"Decode":    func() error { var value int64; return node.Decode(&value) },
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := getter()
+		runtime.ReadMemStats(&after)
+		if err == nil {
+			t.Errorf("%s() expected error", name)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Errorf("%s() allocated %d bytes", name, allocated)
+		}
+	}
+}
+
+func TestNode_GetNumberString(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     *Node
+		expected string
+		err      bool
+	}{
+		{name: "parsed", node: Must(Unmarshal([]byte(`1.50E+10`))), expected: "1.50E+10"},
+		{name: "NumericNode", node: NumericNode("", 1.5e10), expected: "15000000000"},
+		{name: "String", node: StringNode("", "1"), err: true},
+		{name: "nil", node: nil, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := test.node.GetNumberString()
+			if (err != nil) != test.err {
+				t.Errorf("GetNumberString() unexpected error: %v", err)
+			} else if value != test.expected {
+				t.Errorf("GetNumberString() wrong value: %s", value)
+			}
+		})
+	}
+	for _, getter := range []func(*Node) error{
+		func(node *Node) (err error) { _, err = node.GetInt64(); return },
+		func(node *Node) (err error) { _, err = node.GetUint64(); return },
+		func(node *Node) (err error) { _, err = node.GetBigInt(); return },
+		func(node *Node) (err error) { _, err = node.GetBigFloat(); return },
+	} {
+		if getter(NullNode("")) == nil {
+			t.Errorf("getter should return error for the Null node")
+		}
+	}
+}
+
 func TestNode_GetString(t *testing.T) {
 	root, err := Unmarshal([]byte(`"123"`))
 	if err != nil {
@@ -689,10 +797,10 @@ func TestNode_Keys(t *testing.T) {
 	if len(value) != 2 {
 		t.Errorf("Wrong root.Keys()")
 	}
-	if value[0] != "foo" && value[0] != "bar" {
+	if value[0] != "foo" {
 		t.Errorf("Wrong value in 0")
 	}
-	if value[1] != "foo" && value[1] != "bar" {
+	if value[1] != "bar" {
 		t.Errorf("Wrong value in 1")
 	}
 	if (*Node)(nil).Keys() != nil {
@@ -700,6 +808,28 @@ func TestNode_Keys(t *testing.T) {
 	}
 }
 
+func TestNode_Keys_order(t *testing.T) {
+	tests := []struct {
+		name     string
+		root     *Node
+		expected []string
+	}{
+		{name: "parsed", root: Must(Unmarshal([]byte(`{"z":1,"a":2,"m":3,"b":4}`))), expected: []string{"z", "a", "m", "b"}},
+		{name: "duplicates", root: Must(Unmarshal([]byte(`{"z":1,"a":2,"z":3}`))), expected: []string{"z", "a"}},
+		{name: "json5", root: Must(UnmarshalJSON5([]byte(`{z:1,a:2,m:3}`))), expected: []string{"z", "a", "m"}},
+		{name: "constructor", root: ObjectNode("", map[string]*Node{"z": NullNode(""), "a": NullNode("")}), expected: []string{"a", "z"}},
+		{name: "array", root: Must(Unmarshal([]byte(`[1,2,3]`))), expected: []string{"0", "1", "2"}},
+		{name: "empty", root: Must(Unmarshal([]byte(`{}`))), expected: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if keys := test.root.Keys(); !reflect.DeepEqual(keys, test.expected) {
+				t.Errorf("Keys() wrong order: %v, expected %v", keys, test.expected)
+			}
+		})
+	}
+}
+
 func TestNode_Size(t *testing.T) {
 	root, err := Unmarshal([]byte(`[1,2,3,4]`))
 	if err != nil {
@@ -749,6 +879,60 @@ func TestNode_Source(t *testing.T) {
 	}
 }
 
+func TestNode_Offset(t *testing.T) {
+	data := []byte("{\n  \"foo\": [true, \"ключ\"],\n  \"bar\": {\"baz\": -1.5}\n}")
+	root := Must(Unmarshal(data))
+	tests := []struct {
+		name   string
+		path   string
+		source string
+		line   int
+		column int
+	}{
+		{name: "root", path: "$", source: string(data), line: 1, column: 1},
+		{name: "array", path: "$.foo", source: `[true, "ключ"]`, line: 2, column: 10},
+		{name: "bool", path: "$.foo[0]", source: `true`, line: 2, column: 11},
+		{name: "string", path: "$.foo[1]", source: `"ключ"`, line: 2, column: 17},
+		{name: "object", path: "$.bar", source: `{"baz": -1.5}`, line: 3, column: 10},
+		{name: "numeric", path: "$.bar.baz", source: `-1.5`, line: 3, column: 18},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err := root.JSONPath(test.path)
+			if err != nil || len(nodes) != 1 {
+				t.Fatalf("JSONPath() unexpected result: %v, %v", nodes, err)
+			}
+			start, end := nodes[0].Offset()
+			if string(data[start:end]) != test.source {
+				t.Errorf("Offset() wrong borders: %d:%d", start, end)
+			}
+			line, column := nodes[0].Position()
+			if line != test.line || column != test.column {
+				t.Errorf("Position() wrong position: %d:%d", line, column)
+			}
+		})
+	}
+}
+
+func TestNode_Offset_unknown(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"foo":"bar"}`)))
+	if err := root.MustKey("foo").SetString("baz"); err != nil {
+		t.Fatalf("SetString() unexpected error: %s", err)
+	}
+	nodes := []*Node{nil, NullNode(""), root.MustKey("foo")}
+	for _, node := range nodes {
+		if start, end := node.Offset(); start != -1 || end != -1 {
+			t.Errorf("Offset() wrong result for %s: %d:%d", node, start, end)
+		}
+		if line, column := node.Position(); line != 0 || column != 0 {
+			t.Errorf("Position() wrong result for %s: %d:%d", node, line, column)
+		}
+	}
+	if start, end := root.Offset(); start != 0 || end != 13 {
+		t.Errorf("Offset() wrong result for dirty root: %d:%d", start, end)
+	}
+}
+
 func TestNode_String(t *testing.T) {
 	root, err := Unmarshal([]byte(`{"foo":true,"bar":null}`))
 	if err != nil {
@@ -2009,3 +2193,98 @@ func TestNode_Value(t *testing.T) {
 		})
 	}
 }
+
+func TestNode_MarshalJSON(t *testing.T) {
+	type inner struct {
+		Value *Node `json:"value"`
+	}
+	type envelope struct {
+		ID      int     `json:"id"`
+		Payload *Node   `json:"payload"`
+		Empty   *Node   `json:"empty"`
+		Omitted *Node   `json:"omitted,omitempty"`
+		Zero    Node    `json:"zero"`
+		Inner   inner   `json:"inner"`
+		List    []*Node `json:"list"`
+	}
+	payload := Must(Unmarshal([]byte(`{"b": [1, 2], "a": "<x>"}`)))
+	if err := payload.AppendObject("c", StringNode("", "d")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	value := &envelope{
+		ID:      1,
+		Payload: payload,
+		Inner:   inner{Value: Must(Unmarshal([]byte(` [ true ] `)))},
+		List:    []*Node{NullNode(""), NumericNode("", 1.5), nil},
+	}
+	result, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %s", err)
+	}
+	// encoding/json escapes HTML symbols in the result of MarshalJSON
+	expected := `{"id":1,"payload":{"b":[1,2],"a":"\u003cx\u003e","c":"d"},"empty":null,"zero":null,"inner":{"value":[true]},"list":[null,1.5,null]}`
+	if string(result) != expected {
+		t.Errorf("json.Marshal() wrong result:\n%s\n%s", result, expected)
+	}
+	if result, err = (*Node)(nil).MarshalJSON(); err != nil || string(result) != "null" {
+		t.Errorf("MarshalJSON() wrong result of nil: %s, %v", result, err)
+	}
+	if _, err = json.Marshal(ArrayNode("", []*Node{{_type: Numeric, dirty: true}})); err == nil {
+		t.Errorf("json.Marshal() expected error")
+	}
+}
+
+func TestNode_UnmarshalJSON(t *testing.T) {
+	type inner struct {
+		Value Node `json:"value"`
+	}
+	type envelope struct {
+		ID      int     `json:"id"`
+		Payload *Node   `json:"payload"`
+		Empty   *Node   `json:"empty"`
+		Missed  *Node   `json:"missed"`
+		Inner   inner   `json:"inner"`
+		List    []*Node `json:"list"`
+	}
+	data := []byte(`{"id": 1, "payload": {"a": [1, {"b": "c"}]}, "empty": null, "inner": {"value": null}, "list": ["x", 2]}`)
+	var value envelope
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %s", err)
+	}
+	if value.ID != 1 || value.Empty != nil || value.Missed != nil {
+		t.Errorf("json.Unmarshal() wrong value: %#v", value)
+	}
+	if result := value.Payload.String(); result != `{"a": [1, {"b": "c"}]}` {
+		t.Errorf("json.Unmarshal() wrong payload: %s", result)
+	}
+	if path := value.Payload.MustKey("a").MustIndex(1).MustKey("b").Path(); path != "$['a'][1]['b']" {
+		t.Errorf("json.Unmarshal() wrong path: %s", path)
+	}
+	if !value.Inner.Value.IsNull() {
+		t.Errorf("json.Unmarshal() wrong inner value: %s", value.Inner.Value.String())
+	}
+	if len(value.List) != 2 || value.List[0].MustString() != "x" || value.List[1].MustNumeric() != 2 {
+		t.Errorf("json.Unmarshal() wrong list: %v", value.List)
+	}
+
+	// source data can be changed after the unmarshal
+	copy(data, bytes.Repeat([]byte{' '}, len(data)))
+	if err := value.Payload.MustKey("a").AppendArray(BoolNode("", true)); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	if result := value.Payload.String(); result != `{"a":[1,{"b": "c"},true]}` {
+		t.Errorf("json.Unmarshal() wrong payload: %s", result)
+	}
+	result, err := json.Marshal(&value)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %s", err)
+	}
+	if string(result) != `{"id":1,"payload":{"a":[1,{"b":"c"},true]},"empty":null,"missed":null,"inner":{"value":null},"list":["x",2]}` {
+		t.Errorf("json.Marshal() wrong result: %s", result)
+	}
+
+	node := new(Node)
+	if err = node.UnmarshalJSON([]byte(`{"a":`)); err == nil {
+		t.Errorf("UnmarshalJSON() expected error")
+	}
+}
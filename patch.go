@@ -0,0 +1,276 @@
+package ajson
+
+import (
+	"strings"
+)
+
+// patchOperation is the single operation of JSON Patch document
+type patchOperation struct {
+	op    string
+	path  []string
+	from  []string
+	value *Node
+}
+
+// ApplyPatch applies JSON Patch (RFC 6902) document to the root node. Operations `add`, `remove`, `replace`, `move`,
+// `copy` and `test` are supported. The patch should be an array of operation objects, e.g.:
+//
+//	[{"op": "replace", "path": "/store/book/0/price", "value": 9.95}, {"op": "remove", "path": "/store/bicycle"}]
+//
+// Application is atomic: operations change the tree itself, and if one of them fails, all the changed nodes are
+// restored, so the tree is left unchanged. Nodes obtained before the call stay in the tree, and only the containers
+// on the paths of the operations are loaded in the lazy mode. Errors contain the index of the failed operation and
+// its path.
+func ApplyPatch(root *Node, patch *Node) error {
+	if root == nil || patch == nil {
+		return errorUnparsed()
+	}
+	if !patch.IsArray() {
+		return errorRequest("patch should be an array of operations")
+	}
+	journal := new(patchJournal)
+	for i, operation := range patch.Inheritors() {
+		current, err := parsePatchOperation(operation)
+		if err == nil {
+			err = current.apply(root, journal)
+		}
+		if err != nil {
+			journal.rollback()
+			return errorRequest("patch operation %d %s", i, reason(err))
+		}
+	}
+	return nil
+}
+
+// parsePatchOperation returns the operation of JSON Patch document
+func parsePatchOperation(node *Node) (result *patchOperation, err error) {
+	if !node.IsObject() {
+		return nil, errorRequest("failed: operation should be an object")
+	}
+	member := func(name string) (string, error) {
+		value, ok := node.child(name)
+		if !ok || !value.IsString() {
+			return "", errorRequest("failed: member '%s' should be a string", name)
+		}
+		return value.GetString()
+	}
+	result = new(patchOperation)
+	if result.op, err = member("op"); err != nil {
+		return nil, err
+	}
+	path, err := member("path")
+	if err != nil {
+		return nil, err
+	}
+	if result.path, err = parsePatchPointer(path); err != nil {
+		return nil, err
+	}
+	switch result.op {
+	case "add", "replace", "test":
+		value, ok := node.child("value")
+		if !ok {
+			return nil, errorRequest("'%s' failed at '%s': member 'value' is missing", result.op, path)
+		}
+		result.value = value.Clone()
+	case "move", "copy":
+		from, err := member("from")
+		if err != nil {
+			return nil, err
+		}
+		if result.from, err = parsePatchPointer(from); err != nil {
+			return nil, err
+		}
+	case "remove":
+	default:
+		return nil, errorRequest("failed: unknown operation '%s'", result.op)
+	}
+	return result, nil
+}
+
+// parsePatchPointer parses pointer with the error message of JSON Patch
+func parsePatchPointer(pointer string) ([]string, error) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, errorRequest("failed: wrong pointer '%s'", pointer)
+	}
+	return tokens, nil
+}
+
+// apply applies operation to the document, changed nodes are recorded in the journal
+func (o *patchOperation) apply(document *Node, journal *patchJournal) (err error) {
+	switch o.op {
+	case "add":
+		err = patchAdd(document, o.path, o.value, journal)
+	case "remove":
+		_, err = patchRemove(document, o.path, journal)
+	case "replace":
+		err = patchReplace(document, o.path, o.value, journal)
+	case "move":
+		if formatPointer(o.from) == formatPointer(o.path) {
+			_, err = ResolvePointer(document, formatPointer(o.from))
+			break
+		}
+		if strings.HasPrefix(formatPointer(o.path), formatPointer(o.from)+"/") {
+			return errorRequest("'move' failed at '%s': location can't be moved into one of its children", formatPointer(o.path))
+		}
+		var value *Node
+		if value, err = patchRemove(document, o.from, journal); err == nil {
+			err = patchAdd(document, o.path, value, journal)
+		}
+	case "copy":
+		var value *Node
+		if value, err = ResolvePointer(document, formatPointer(o.from)); err == nil {
+			err = patchAdd(document, o.path, value.Clone(), journal)
+		}
+	case "test":
+		var value *Node
+		if value, err = ResolvePointer(document, formatPointer(o.path)); err == nil {
+			var equal bool
+			if equal, err = value.Eq(o.value); err == nil && !equal {
+				err = errorRequest("value is not equal")
+			}
+		}
+	}
+	if err != nil {
+		return errorRequest("'%s' failed at '%s': %s", o.op, formatPointer(o.path), reason(err))
+	}
+	return nil
+}
+
+// patchAdd adds the value to the location in the document
+func patchAdd(document *Node, path []string, value *Node, journal *patchJournal) error {
+	if len(path) == 0 {
+		return patchReplace(document, path, value, journal)
+	}
+	parent, err := ResolvePointer(document, formatPointer(path[:len(path)-1]))
+	if err != nil {
+		return err
+	}
+	token := path[len(path)-1]
+	switch parent.Type() {
+	case Object:
+		journal.change(parent)
+		return parent.AppendObject(token, value)
+	case Array:
+		if token == "-" {
+			journal.change(parent)
+			return parent.AppendArray(value)
+		}
+		index, ok := pointerIndex(token)
+		if !ok {
+			return errorRequest("wrong index '%s'", token)
+		}
+		journal.change(parent)
+		return parent.insertNode(index, value)
+	}
+	return errorRequest("parent is not a container")
+}
+
+// patchRemove removes the value from the location in the document, and returns it
+func patchRemove(document *Node, path []string, journal *patchJournal) (*Node, error) {
+	if len(path) == 0 {
+		return nil, errorRequest("root can't be removed")
+	}
+	value, err := ResolvePointer(document, formatPointer(path))
+	if err != nil {
+		return nil, err
+	}
+	journal.change(value.parent)
+	return value, value.Delete()
+}
+
+// patchReplace replaces the value at the location in the document, the root node keeps its place in the tree
+func patchReplace(document *Node, path []string, value *Node, journal *patchJournal) (err error) {
+	target := document
+	if len(path) != 0 {
+		if target, err = ResolvePointer(document, formatPointer(path)); err != nil {
+			return err
+		}
+	}
+	journal.save(target)
+	for _, child := range value.children {
+		// children of the moved node will refer to the target
+		journal.place(child)
+	}
+	return target.replace(value)
+}
+
+// patchJournal keeps the state of the nodes, changed by ApplyPatch, to restore the tree if an operation fails
+type patchJournal struct {
+	records []patchRecord
+	saved   map[*Node]bool
+	placed  map[*Node]bool
+}
+
+// patchRecord is the state of the node before the change: the whole node, or only its place in the tree
+type patchRecord struct {
+	node   *Node
+	state  *Node
+	parent *Node
+	key    *string
+	index  *int
+	dirty  bool
+}
+
+// change records the container and the places of its children, before the children are added or removed
+func (j *patchJournal) change(n *Node) {
+	j.save(n)
+	for _, child := range n.children {
+		j.place(child)
+	}
+}
+
+// save records the whole node and the places of its parents, which will be marked as dirty
+func (j *patchJournal) save(n *Node) {
+	if j.saved == nil {
+		j.saved = make(map[*Node]bool)
+	}
+	if !j.saved[n] {
+		j.saved[n] = true
+		n.load()
+		state := &Node{}
+		*state = *n
+		state.keys = append([]string(nil), n.keys...)
+		if n.children != nil {
+			state.children = make(map[string]*Node, len(n.children))
+			for key, child := range n.children {
+				state.children[key] = child
+			}
+		}
+		j.records = append(j.records, patchRecord{node: n, state: state})
+	}
+	for parent := n.parent; parent != nil; parent = parent.parent {
+		j.place(parent)
+	}
+}
+
+// place records the parent, the key, the index and the dirty flag of the node
+func (j *patchJournal) place(n *Node) {
+	if j.placed == nil {
+		j.placed = make(map[*Node]bool)
+	}
+	if !j.placed[n] {
+		j.placed[n] = true
+		j.records = append(j.records, patchRecord{node: n, parent: n.parent, key: n.key, index: n.index, dirty: n.dirty})
+	}
+}
+
+// rollback restores the recorded nodes in the reverse order, so each node gets its first recorded state
+func (j *patchJournal) rollback() {
+	for i := len(j.records) - 1; i >= 0; i-- {
+		record := j.records[i]
+		if record.state != nil {
+			*record.node = *record.state
+		} else {
+			record.node.parent, record.node.key, record.node.index, record.node.dirty = record.parent, record.key, record.index, record.dirty
+		}
+	}
+}
+
+// reason returns the message of the error, without its type
+func reason(err error) string {
+	if value, ok := err.(Error); ok && value.Message != "" {
+		return value.Message
+	}
+	return err.Error()
+}
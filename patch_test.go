@@ -0,0 +1,304 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleApplyPatch() {
+	root := Must(Unmarshal([]byte(`{"store": {"book": [{"title": "Moby Dick", "price": 8.99}], "bicycle": {"color": "red"}}}`)))
+	patch := Must(Unmarshal([]byte(`[
+		{"op": "test", "path": "/store/book/0/title", "value": "Moby Dick"},
+		{"op": "replace", "path": "/store/book/0/price", "value": 9.95},
+		{"op": "add", "path": "/store/book/-", "value": {"title": "Sword of Honour", "price": 12.99}},
+		{"op": "remove", "path": "/store/bicycle"}
+	]`)))
+	if err := ApplyPatch(root, patch); err != nil {
+		panic(err)
+	}
+	fmt.Println(root)
+
+	patch = Must(Unmarshal([]byte(`[
+		{"op": "remove", "path": "/store/book/0"},
+		{"op": "test", "path": "/store/book/0/title", "value": "Moby Dick"}
+	]`)))
+	fmt.Println(ApplyPatch(root, patch))
+	fmt.Println(root)
+	// Output:
+	// {"store":{"book":[{"title":"Moby Dick","price":9.95},{"title": "Sword of Honour", "price": 12.99}]}}
+	// wrong request: patch operation 1 'test' failed at '/store/book/0/title': value is not equal
+	// {"store":{"book":[{"title":"Moby Dick","price":9.95},{"title": "Sword of Honour", "price": 12.99}]}}
+}
+
+func TestApplyPatch(t *testing.T) {
+	// examples from RFC 6902, Appendix A
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		expected string
+	}{
+		{name: "A.1 adding an object member", document: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`, expected: `{"baz": "qux", "foo": "bar"}`},
+		{name: "A.2 adding an array element", document: `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, expected: `{"foo": ["bar", "qux", "baz"]}`},
+		{name: "A.3 removing an object member", document: `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`, expected: `{"foo": "bar"}`},
+		{name: "A.4 removing an array element", document: `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`, expected: `{"foo": ["bar", "baz"]}`},
+		{name: "A.5 replacing a value", document: `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`, expected: `{"baz": "boo", "foo": "bar"}`},
+		{name: "A.6 moving a value", document: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`},
+		{name: "A.7 moving an array element", document: `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, expected: `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{name: "A.8 testing a value: success", document: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`},
+		{name: "A.10 adding a nested member object", document: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`, expected: `{"foo": "bar", "child": {"grandchild": {}}}`},
+		{name: "A.11 ignoring unrecognized elements", document: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`, expected: `{"foo": "bar", "baz": "qux"}`},
+		{name: "A.14 ~ escape ordering", document: `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": 10}]`, expected: `{"/": 9, "~1": 10}`},
+		{name: "A.16 adding an array value", document: `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, expected: `{"foo": ["bar", ["abc", "def"]]}`},
+		{name: "add replaces member", document: `{"a": 1, "b": 2}`,
+			patch: `[{"op": "add", "path": "/a", "value": [3]}]`, expected: `{"a": [3], "b": 2}`},
+		{name: "add to the end", document: `[1, 2]`,
+			patch: `[{"op": "add", "path": "/2", "value": 3}, {"op": "add", "path": "/0", "value": 0}]`, expected: `[0, 1, 2, 3]`},
+		{name: "add root", document: `{"a": 1}`,
+			patch: `[{"op": "add", "path": "", "value": [true]}, {"op": "add", "path": "/-", "value": null}]`, expected: `[true, null]`},
+		{name: "replace root", document: `[1]`,
+			patch: `[{"op": "replace", "path": "", "value": {"a": "b"}}]`, expected: `{"a": "b"}`},
+		{name: "move to the same location", document: `{"a": 1}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/a"}]`, expected: `{"a": 1}`},
+		{name: "move to root", document: `{"a": {"b": 1}}`,
+			patch: `[{"op": "move", "from": "/a", "path": ""}]`, expected: `{"b": 1}`},
+		{name: "copy", document: `{"a": {"b": [1]}, "c": 2}`,
+			patch:    `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/b/-", "value": 2}]`,
+			expected: `{"a": {"b": [1]}, "c": {"b": [1, 2]}}`},
+		{name: "test deep", document: `{"a": {"b": [1, {"c": null}]}}`,
+			patch: `[{"op": "test", "path": "/a", "value": {"b": [1.0, {"c": null}]}}]`, expected: `{"a": {"b": [1, {"c": null}]}}`},
+		{name: "empty", document: `{"a": 1}`, patch: `[]`, expected: `{"a": 1}`},
+		{name: "test after add", document: `[1]`,
+			patch:    `[{"op": "test", "path": "", "value": [1]}, {"op": "add", "path": "/-", "value": 2}, {"op": "test", "path": "", "value": [1, 2]}]`,
+			expected: `[1, 2]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				root := Must(UnmarshalWithOptions([]byte(test.document), Options{Lazy: lazy}))
+				patch := Must(Unmarshal([]byte(test.patch)))
+				source := patch.String()
+				if err := ApplyPatch(root, patch); err != nil {
+					t.Fatalf("ApplyPatch() unexpected error: %s", err)
+				}
+				if ok, err := root.Eq(Must(Unmarshal([]byte(test.expected)))); err != nil || !ok {
+					t.Errorf("ApplyPatch() wrong result: %s", root)
+				}
+				if patch.String() != source || patch.IsDirty() {
+					t.Errorf("ApplyPatch() patch was changed: %s", patch)
+				}
+				if _, err := Unmarshal([]byte(root.String())); err != nil {
+					t.Errorf("ApplyPatch() produced wrong document: %s", root)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPatch_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		message  string
+	}{
+		{name: "A.9 testing a value: error", document: `{"baz": "qux"}`,
+			patch:   `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			message: `wrong request: patch operation 0 'test' failed at '/baz': value is not equal`},
+		{name: "A.12 adding to a nonexistent target", document: `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			message: `wrong request: patch operation 0 'add' failed at '/baz/bat': unresolved pointer '/baz'`},
+		{name: "A.15 comparing strings and numbers", document: `{"/": 9, "~1": 10}`,
+			patch:   `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			message: `wrong request: patch operation 0 'test' failed at '/~01': value is not equal`},
+		{name: "not an array", document: `{}`, patch: `{}`,
+			message: `wrong request: patch should be an array of operations`},
+		{name: "not an object", document: `{}`, patch: `[{"op": "test", "path": "", "value": {}}, 1]`,
+			message: `wrong request: patch operation 1 failed: operation should be an object`},
+		{name: "missing op", document: `{}`, patch: `[{"path": "/a"}]`,
+			message: `wrong request: patch operation 0 failed: member 'op' should be a string`},
+		{name: "unknown op", document: `{}`, patch: `[{"op": "push", "path": "/a"}]`,
+			message: `wrong request: patch operation 0 failed: unknown operation 'push'`},
+		{name: "wrong path", document: `{}`, patch: `[{"op": "remove", "path": "a"}]`,
+			message: `wrong request: patch operation 0 failed: wrong pointer 'a'`},
+		{name: "missing from", document: `{}`, patch: `[{"op": "copy", "path": "/a"}]`,
+			message: `wrong request: patch operation 0 failed: member 'from' should be a string`},
+		{name: "missing value", document: `{}`, patch: `[{"op": "add", "path": "/a"}]`,
+			message: `wrong request: patch operation 0 'add' failed at '/a': member 'value' is missing`},
+		{name: "remove missing", document: `{"a": [1]}`, patch: `[{"op": "remove", "path": "/a/1"}]`,
+			message: `wrong request: patch operation 0 'remove' failed at '/a/1': unresolved pointer '/a/1'`},
+		{name: "remove root", document: `{}`, patch: `[{"op": "remove", "path": ""}]`,
+			message: `wrong request: patch operation 0 'remove' failed at '': root can't be removed`},
+		{name: "replace missing", document: `{}`, patch: `[{"op": "replace", "path": "/a", "value": 1}]`,
+			message: `wrong request: patch operation 0 'replace' failed at '/a': unresolved pointer '/a'`},
+		{name: "add out of index", document: `[1]`, patch: `[{"op": "add", "path": "/2", "value": 1}]`,
+			message: `wrong request: patch operation 0 'add' failed at '/2': out of index 2`},
+		{name: "add wrong index", document: `[1]`, patch: `[{"op": "add", "path": "/01", "value": 1}]`,
+			message: `wrong request: patch operation 0 'add' failed at '/01': wrong index '01'`},
+		{name: "add into scalar", document: `{"a": 1}`, patch: `[{"op": "add", "path": "/a/b", "value": 1}]`,
+			message: `wrong request: patch operation 0 'add' failed at '/a/b': parent is not a container`},
+		{name: "move into child", document: `{"a": {"b": {}}}`, patch: `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`,
+			message: `wrong request: patch operation 0 'move' failed at '/a/b/c': location can't be moved into one of its children`},
+		{name: "move missing", document: `{}`, patch: `[{"op": "move", "from": "/a", "path": "/a"}]`,
+			message: `wrong request: patch operation 0 'move' failed at '/a': unresolved pointer '/a'`},
+		{name: "copy missing", document: `{}`, patch: `[{"op": "copy", "from": "/a", "path": "/b"}]`,
+			message: `wrong request: patch operation 0 'copy' failed at '/b': unresolved pointer '/a'`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(test.document)))
+			err := ApplyPatch(root, Must(Unmarshal([]byte(test.patch))))
+			if err == nil {
+				t.Fatalf("ApplyPatch() expected error")
+			}
+			if err.Error() != test.message {
+				t.Errorf("ApplyPatch() wrong error:\n%s\n%s", err, test.message)
+			}
+			if root.String() != test.document || root.IsDirty() {
+				t.Errorf("ApplyPatch() document was changed: %s", root)
+			}
+		})
+	}
+	if err := ApplyPatch(nil, ArrayNode("", nil)); err == nil {
+		t.Errorf("ApplyPatch() expected error")
+	}
+}
+
+func TestApplyPatch_atomic(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, 2, 3], "b": {"c": true}}`)))
+	child := root.MustKey("a")
+	first, last := child.MustIndex(0), child.MustIndex(2)
+	patch := Must(Unmarshal([]byte(`[
+		{"op": "remove", "path": "/a/0"},
+		{"op": "add", "path": "/b/d", "value": null},
+		{"op": "move", "from": "/b", "path": "/a/0"},
+		{"op": "replace", "path": "/x", "value": 1}
+	]`)))
+	if err := ApplyPatch(root, patch); err == nil {
+		t.Fatalf("ApplyPatch() expected error")
+	}
+	if root.String() != `{"a": [1, 2, 3], "b": {"c": true}}` || child.Parent() != root || child.Size() != 3 {
+		t.Errorf("ApplyPatch() document was changed: %s", root)
+	}
+	if root.IsDirty() || child.IsDirty() || root.MustKey("b").IsDirty() {
+		t.Errorf("ApplyPatch() document was marked as dirty")
+	}
+	if root.MustKey("a") != child || child.MustIndex(0) != first || first.Index() != 0 || last.Index() != 2 || last.Parent() != child {
+		t.Errorf("ApplyPatch() wrong nodes of the document")
+	}
+	if err := first.SetNumeric(4); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	if root.String() != `{"a":[4,2,3],"b":{"c": true}}` {
+		t.Errorf("SetNumeric() wrong result: %s", root)
+	}
+}
+
+func TestApplyPatch_rollback(t *testing.T) {
+	data := `{"a": [1, {"b": 2}, 3], "c": {"d": [4, 5]}}`
+	for _, operations := range []string{
+		`{"op": "replace", "path": "", "value": [1]}`,
+		`{"op": "add", "path": "", "value": {"y": 1}}`,
+		`{"op": "move", "from": "/c", "path": ""}`,
+		`{"op": "move", "from": "/a/1", "path": "/c/d/0"}, {"op": "remove", "path": "/a/0"}`,
+		`{"op": "copy", "from": "/c", "path": "/a/-"}, {"op": "add", "path": "/c", "value": null}`,
+		`{"op": "add", "path": "/c/d/1", "value": 0}, {"op": "replace", "path": "/a", "value": {}}`,
+	} {
+		t.Run(operations, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(data)))
+			var nodes []*Node
+			root.Walk(func(node *Node, _ int) WalkAction {
+				nodes = append(nodes, node)
+				return WalkContinue
+			})
+			patch := Must(Unmarshal([]byte(`[` + operations + `, {"op": "test", "path": "/x", "value": 1}]`)))
+			if err := ApplyPatch(root, patch); err == nil {
+				t.Fatalf("ApplyPatch() expected error")
+			}
+			if root.String() != data || root.IsDirty() {
+				t.Errorf("ApplyPatch() document was changed: %s", root)
+			}
+			var result []*Node
+			root.Walk(func(node *Node, _ int) WalkAction {
+				result = append(result, node)
+				return WalkContinue
+			})
+			if len(result) != len(nodes) {
+				t.Fatalf("ApplyPatch() nodes were changed: %d", len(result))
+			}
+			for i, node := range nodes {
+				if value, err := ResolvePointer(root, node.Pointer()); err != nil || value != node || result[i] != node || node.IsDirty() {
+					t.Errorf("ApplyPatch() node was changed: %s", node.Pointer())
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPatch_references(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, 2], "b": {"c": true}, "c": 3}`)))
+	a, b, c := root.MustKey("a"), root.MustKey("b"), root.MustKey("c")
+	patch := Must(Unmarshal([]byte(`[
+		{"op": "add", "path": "/a/0", "value": 0},
+		{"op": "move", "from": "/b", "path": "/d"},
+		{"op": "replace", "path": "/c", "value": 4}
+	]`)))
+	if err := ApplyPatch(root, patch); err != nil {
+		t.Fatalf("ApplyPatch() unexpected error: %s", err)
+	}
+	if root.MustKey("a") != a || root.MustKey("d") != b || root.MustKey("c") != c || b.Parent() != root || c.Parent() != root {
+		t.Errorf("ApplyPatch() nodes were detached")
+	}
+	if err := b.AppendObject("e", NullNode("")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := c.SetNumeric(5); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	if root.String() != `{"a":[0,1,2],"c":5,"d":{"c":true,"e":null}}` {
+		t.Errorf("ApplyPatch() wrong result: %s", root)
+	}
+}
+
+func TestApplyPatch_lazy(t *testing.T) {
+	root := Must(UnmarshalWithOptions([]byte(`{"a": {"b": [1, 2]}, "c": {"d": [3]}}`), Options{Lazy: true}))
+	patch := Must(Unmarshal([]byte(`[{"op": "add", "path": "/a/b/-", "value": 3}, {"op": "test", "path": "/x", "value": 1}]`)))
+	if err := ApplyPatch(root, patch); err == nil {
+		t.Fatalf("ApplyPatch() expected error")
+	}
+	patch = Must(Unmarshal([]byte(`[{"op": "add", "path": "/a/b/-", "value": 3}]`)))
+	if err := ApplyPatch(root, patch); err != nil {
+		t.Fatalf("ApplyPatch() unexpected error: %s", err)
+	}
+	if len(root.children["c"].children) != 0 {
+		t.Errorf("ApplyPatch() untouched container was loaded")
+	}
+	if root.String() != `{"a":{"b":[1,2,3]},"c":{"d": [3]}}` {
+		t.Errorf("ApplyPatch() wrong result: %s", root)
+	}
+}
+
+func TestApplyPatch_child(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": 1}, "c": 2}`)))
+	node := root.MustKey("a")
+	if err := ApplyPatch(node, Must(Unmarshal([]byte(`[{"op": "add", "path": "/d", "value": 3}]`)))); err != nil {
+		t.Fatalf("ApplyPatch() unexpected error: %s", err)
+	}
+	if root.String() != `{"a":{"b":1,"d":3},"c":2}` || root.MustKey("a") != node || node.Parent() != root {
+		t.Errorf("ApplyPatch() wrong result: %s", root)
+	}
+}
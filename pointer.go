@@ -0,0 +1,120 @@
+package ajson
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// Pointer returns full JSON Pointer (RFC 6901) of current Node, e.g. `/store/book/0/title`.
+// JSON Pointer of the root node is an empty string.
+func (n *Node) Pointer() string {
+	if n == nil || n.parent == nil {
+		return ""
+	}
+	if n.key != nil {
+		return n.parent.Pointer() + "/" + pointerEscaper.Replace(n.Key())
+	}
+	return n.parent.Pointer() + "/" + strconv.Itoa(n.Index())
+}
+
+// GetPointer returns the node by JSON Pointer (RFC 6901), relative to the current node.
+func (n *Node) GetPointer(pointer string) (*Node, error) {
+	return ResolvePointer(n, pointer)
+}
+
+// Pointers returns calculated JSON Pointers of underlying nodes, e.g. the result of JSONPath.
+func Pointers(array []*Node) []string {
+	result := make([]string, 0, len(array))
+	for _, element := range array {
+		result = append(result, element.Pointer())
+	}
+	return result
+}
+
+// ResolvePointer returns the node by JSON Pointer (RFC 6901), e.g. `/store/book/0/title`. Empty pointer refers to
+// the root node itself.
+func ResolvePointer(root *Node, pointer string) (*Node, error) {
+	if root == nil {
+		return nil, errorUnparsed()
+	}
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	node := root
+	for i, token := range tokens {
+		child, ok := node.pointerChild(token)
+		if !ok {
+			return nil, errorRequest("unresolved pointer '%s'", formatPointer(tokens[:i+1]))
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// ParsePointer will parse JSON Pointer (RFC 6901) and return its unescaped reference tokens.
+// Example:
+//
+//	result, _ := ParsePointer("/store/book/0/a~1b")
+//	result == []string{"store", "book", "0", "a/b"}
+func ParsePointer(pointer string) (result []string, err error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, errorRequest("wrong pointer '%s': it should start with '/'", pointer)
+	}
+	result = strings.Split(pointer[1:], "/")
+	for i, token := range result {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, errorRequest("wrong pointer '%s': wrong escape sequence", pointer)
+			}
+		}
+		result[i] = pointerUnescaper.Replace(token)
+	}
+	return result, nil
+}
+
+// formatPointer returns JSON Pointer of the unescaped reference tokens
+func formatPointer(tokens []string) string {
+	var result strings.Builder
+	for _, token := range tokens {
+		result.WriteByte('/')
+		result.WriteString(pointerEscaper.Replace(token))
+	}
+	return result.String()
+}
+
+// pointerChild returns the child by the reference token: the key of an Object, or the index of an Array
+func (n *Node) pointerChild(token string) (*Node, bool) {
+	switch n.Type() {
+	case Object:
+		return n.child(token)
+	case Array:
+		if _, ok := pointerIndex(token); !ok {
+			return nil, false
+		}
+		return n.child(token)
+	}
+	return nil, false
+}
+
+// pointerIndex returns the index of an Array by the reference token: digits without leading zeros
+func pointerIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	return index, err == nil
+}
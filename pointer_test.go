@@ -0,0 +1,174 @@
+package ajson
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleResolvePointer() {
+	root := Must(Unmarshal([]byte(`{"store": {"book": [
+		{"author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+		{"author": "Herman Melville", "title": "Moby Dick", "price": 8.99}
+	]}}`)))
+	node, err := ResolvePointer(root, "/store/book/1/title")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(node)
+
+	nodes, err := root.JSONPath("$..book[?(@.price < 8.99)].author")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(Pointers(nodes))
+	// Output:
+	// "Moby Dick"
+	// [/store/book/0/author]
+}
+
+// rfc6901 is the example document from RFC 6901, section 5
+const rfc6901 = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+func TestResolvePointer(t *testing.T) {
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{pointer: ``, expected: `{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"e^f":3,"g|h":4,"i\\j":5,"k\"l":6," ":7,"m~n":8}`},
+		{pointer: `/foo`, expected: `["bar","baz"]`},
+		{pointer: `/foo/0`, expected: `"bar"`},
+		{pointer: `/`, expected: `0`},
+		{pointer: `/a~1b`, expected: `1`},
+		{pointer: `/c%d`, expected: `2`},
+		{pointer: `/e^f`, expected: `3`},
+		{pointer: `/g|h`, expected: `4`},
+		{pointer: `/i\j`, expected: `5`},
+		{pointer: `/k"l`, expected: `6`},
+		{pointer: `/ `, expected: `7`},
+		{pointer: `/m~0n`, expected: `8`},
+	}
+	for _, lazy := range []bool{false, true} {
+		root := Must(UnmarshalWithOptions([]byte(rfc6901), Options{Lazy: lazy}))
+		for _, test := range tests {
+			t.Run(test.pointer, func(t *testing.T) {
+				node, err := ResolvePointer(root, test.pointer)
+				if err != nil {
+					t.Fatalf("ResolvePointer() unexpected error: %s", err)
+				}
+				if ok, err := node.Eq(Must(Unmarshal([]byte(test.expected)))); err != nil || !ok {
+					t.Errorf("ResolvePointer() wrong result: %s", node)
+				}
+				if node.Pointer() != test.pointer {
+					t.Errorf("Pointer() wrong result: %q", node.Pointer())
+				}
+			})
+		}
+	}
+}
+
+func TestResolvePointer_errors(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, {"b": null}], "c~": 1}`)))
+	tests := []struct {
+		pointer string
+		message string
+	}{
+		{pointer: `a`, message: `wrong request: wrong pointer 'a': it should start with '/'`},
+		{pointer: `/c~`, message: `wrong request: wrong pointer '/c~': wrong escape sequence`},
+		{pointer: `/c~2`, message: `wrong request: wrong pointer '/c~2': wrong escape sequence`},
+		{pointer: `/d`, message: `wrong request: unresolved pointer '/d'`},
+		{pointer: `/a/2`, message: `wrong request: unresolved pointer '/a/2'`},
+		{pointer: `/a/-`, message: `wrong request: unresolved pointer '/a/-'`},
+		{pointer: `/a/01`, message: `wrong request: unresolved pointer '/a/01'`},
+		{pointer: `/a/-1`, message: `wrong request: unresolved pointer '/a/-1'`},
+		{pointer: `/a/`, message: `wrong request: unresolved pointer '/a/'`},
+		{pointer: `/a/1/b/c`, message: `wrong request: unresolved pointer '/a/1/b/c'`},
+		{pointer: `/a/1/a~1b`, message: `wrong request: unresolved pointer '/a/1/a~1b'`},
+	}
+	for _, test := range tests {
+		t.Run(test.pointer, func(t *testing.T) {
+			_, err := ResolvePointer(root, test.pointer)
+			if err == nil {
+				t.Fatalf("ResolvePointer() expected error")
+			}
+			if err.Error() != test.message {
+				t.Errorf("ResolvePointer() wrong error: %s", err)
+			}
+		})
+	}
+	if _, err := ResolvePointer(nil, ""); err == nil {
+		t.Errorf("ResolvePointer() expected error")
+	}
+}
+
+func TestNode_GetPointer(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, {"b/c": {"~": true}}]}`)))
+	node, err := root.MustKey("a").GetPointer("/1/b~1c")
+	if err != nil {
+		t.Fatalf("GetPointer() unexpected error: %s", err)
+	}
+	if node.String() != `{"~": true}` {
+		t.Errorf("GetPointer() wrong result: %s", node)
+	}
+	if node.MustKey("~").Pointer() != "/a/1/b~1c/~0" {
+		t.Errorf("Pointer() wrong result: %s", node.MustKey("~").Pointer())
+	}
+	if (*Node)(nil).Pointer() != "" {
+		t.Errorf("Pointer() wrong result for nil")
+	}
+}
+
+func TestPointers(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a/b": [{"~": 1}, {"~": 2}], "c": {"~": 3}}`)))
+	nodes, err := root.JSONPath("$..['~']")
+	if err != nil {
+		t.Fatalf("JSONPath() unexpected error: %s", err)
+	}
+	result := Pointers(nodes)
+	expected := []string{"/c/~0", "/a~1b/0/~0", "/a~1b/1/~0"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Pointers() wrong result: %v", result)
+	}
+	for i, pointer := range result {
+		if node, err := root.GetPointer(pointer); err != nil || node != nodes[i] {
+			t.Errorf("GetPointer() wrong result: %v", err)
+		}
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		pointer  string
+		expected []string
+	}{
+		{pointer: ``, expected: []string{}},
+		{pointer: `/`, expected: []string{""}},
+		{pointer: `//`, expected: []string{"", ""}},
+		{pointer: `/a~01/b~10`, expected: []string{"a~1", "b/0"}},
+	}
+	for _, test := range tests {
+		t.Run(test.pointer, func(t *testing.T) {
+			result, err := ParsePointer(test.pointer)
+			if err != nil {
+				t.Fatalf("ParsePointer() unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("ParsePointer() wrong result: %q", result)
+			}
+			if formatPointer(result) != test.pointer {
+				t.Errorf("formatPointer() wrong result: %q", formatPointer(result))
+			}
+		})
+	}
+}
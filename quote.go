@@ -1,6 +1,9 @@
 package ajson
 
-import "unicode/utf8"
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
 
 // This file was copied from encoding/json library.
 // fixme: https://github.com/spyzhov/ajson/issues/13
@@ -220,12 +223,14 @@ var htmlSafeSet = [utf8.RuneSelf]bool{
 	'\u007f': true,
 }
 
-func quoteString(s string, escapeHTML bool) []byte {
+// quoteString escapes the string s to be a content of JSON string. Besides the escapeHTML, asciiOnly escapes all
+// non-ASCII runes as \uXXXX (with surrogate pairs) and escapeSlash escapes '/' as '\/'.
+func quoteString(s string, escapeHTML, asciiOnly, escapeSlash bool) []byte {
 	result := make([]byte, 0, len(s))
 	start := 0
 	for i := 0; i < len(s); {
 		if b := s[i]; b < utf8.RuneSelf {
-			if htmlSafeSet[b] || (!escapeHTML && safeSet[b]) {
+			if (htmlSafeSet[b] || (!escapeHTML && safeSet[b])) && (b != '/' || !escapeSlash) {
 				i++
 				continue
 			}
@@ -234,7 +239,7 @@ func quoteString(s string, escapeHTML bool) []byte {
 			}
 			result = append(result, '\\')
 			switch b {
-			case '\\', '"':
+			case '\\', '"', '/':
 				result = append(result, b)
 			case '\n':
 				result = append(result, 'n')
@@ -275,6 +280,21 @@ func quoteString(s string, escapeHTML bool) []byte {
 		// and can lead to security holes there. It is valid JSON to
 		// escape them, so we do so unconditionally.
 		// See http://timelessrepo.com/json-isnt-a-javascript-subset for discussion.
+		if asciiOnly {
+			if start < i {
+				result = append(result, s[start:i]...)
+			}
+			if c > 0xFFFF {
+				r1, r2 := utf16.EncodeRune(c)
+				result = appendRune(result, r1)
+				result = appendRune(result, r2)
+			} else {
+				result = appendRune(result, c)
+			}
+			i += size
+			start = i
+			continue
+		}
 		if c == '\u2028' || c == '\u2029' {
 			if start < i {
 				result = append(result, s[start:i]...)
@@ -294,3 +314,8 @@ func quoteString(s string, escapeHTML bool) []byte {
 	}
 	return result
 }
+
+// appendRune appends rune as \uXXXX
+func appendRune(result []byte, r rune) []byte {
+	return append(result, '\\', 'u', hex[r>>12&0xF], hex[r>>8&0xF], hex[r>>4&0xF], hex[r&0xF])
+}
@@ -0,0 +1,9 @@
+//go:build race
+// +build race
+
+package ajson
+
+func init() {
+	// sync.Pool drops items randomly with the race detector, so allocations can't be checked
+	raceEnabled = true
+}
@@ -0,0 +1,120 @@
+package ajson
+
+import (
+	. "github.com/spyzhov/ajson/internal"
+)
+
+// scanner is a resumable walker over the StateTransitionTable. It checks the JSON grammar byte by byte and finds
+// the borders of the top-level values, without creating any Node.
+type scanner struct {
+	state States
+	stack []NodeType
+	key   bool
+}
+
+// reset prepares scanner to read the next top-level value.
+func (s *scanner) reset() {
+	s.state = GO
+	s.stack = s.stack[:0]
+	s.key = false
+}
+
+// feed moves scanner to the next state with the given symbol. It returns false if symbol is not allowed.
+func (s *scanner) feed(c byte) bool {
+	class := C_ETC
+	if c < 128 {
+		class = AsciiClasses[c]
+	}
+	if class == __ {
+		return false
+	}
+	state := StateTransitionTable[s.state][class]
+	if state >= GO {
+		if state == ST && (s.state == OB || s.state == KE) {
+			s.key = true
+		}
+		s.state = state
+		return true
+	}
+
+	switch state {
+	case co: /* { */
+		s.stack = append(s.stack, Object)
+		s.state = OB
+	case bo: /* [ */
+		s.stack = append(s.stack, Array)
+		s.state = AR
+	case ec, cc: /* } */
+		if s.top() != Object {
+			return false
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+		s.state = OK
+	case bc: /* ] */
+		if s.top() != Array {
+			return false
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+		s.state = OK
+	case cm: /* , */
+		switch s.top() {
+		case Object:
+			s.state = KE
+		case Array:
+			s.state = VA
+		default:
+			return false
+		}
+	case cl: /* : */
+		if s.top() != Object {
+			return false
+		}
+		s.state = VA
+	case qt: /* " */
+		if s.key {
+			s.key = false
+			s.state = CO
+		} else {
+			s.state = OK
+		}
+	default: /* syntax error */
+		return false
+	}
+	return true
+}
+
+// top returns type of the current container, or Null for the top level.
+func (s *scanner) top() NodeType {
+	if len(s.stack) == 0 {
+		return Null
+	}
+	return s.stack[len(s.stack)-1]
+}
+
+// done returns true if the top-level value was fully read.
+func (s *scanner) done() bool {
+	return len(s.stack) == 0 && s.state == OK
+}
+
+// complete returns true if the top-level value can be finished by the end of data.
+func (s *scanner) complete() bool {
+	if len(s.stack) != 0 {
+		return false
+	}
+	switch s.state {
+	case OK, ZE, IN, FR, E3:
+		return true
+	}
+	return false
+}
+
+// isNumber returns true if data is a single valid JSON number without whitespaces
+func isNumber(data []byte) bool {
+	var s scanner
+	for _, c := range data {
+		if !s.feed(c) || !isNumberState(s.state) {
+			return false
+		}
+	}
+	return s.complete() && isNumberState(s.state)
+}
@@ -0,0 +1,212 @@
+package ajson
+
+import (
+	"io"
+	"strconv"
+
+	. "github.com/spyzhov/ajson/internal"
+)
+
+// Tokenizer is a pull-style reader of the JSON tokens. It walks through the data with the same state machine as
+// Unmarshal, but doesn't create any Node.
+type Tokenizer struct {
+	data    []byte
+	index   int
+	start   int
+	scanner scanner
+	queue   []Token
+	err     error
+}
+
+// Token is a single lexical element of the JSON data.
+type Token struct {
+	// Type is a kind of the token
+	Type TokenType
+	// Start is an offset of the first byte of the token in the data
+	Start int
+	// End is an offset of the byte right after the token in the data
+	End int
+	// Depth is a count of containers, that contains the token
+	Depth int
+	// Raw is a slice of the source data with the token
+	Raw []byte
+}
+
+// TokenType is a kind of the JSON token.
+type TokenType int
+
+const (
+	// TokenObjectStart is an opening curly bracket: `{`
+	TokenObjectStart TokenType = iota
+	// TokenObjectEnd is a closing curly bracket: `}`
+	TokenObjectEnd
+	// TokenArrayStart is an opening square bracket: `[`
+	TokenArrayStart
+	// TokenArrayEnd is a closing square bracket: `]`
+	TokenArrayEnd
+	// TokenKey is a quoted key of the object element
+	TokenKey
+	// TokenString is a quoted string value
+	TokenString
+	// TokenNumber is a numeric value
+	TokenNumber
+	// TokenBool is a `true` or `false` value
+	TokenBool
+	// TokenNull is a `null` value
+	TokenNull
+)
+
+// NewTokenizer creates a tokenizer for the given JSON data. It will store link to the data.
+func NewTokenizer(data []byte) *Tokenizer {
+	return &Tokenizer{
+		data: data,
+	}
+}
+
+// Next returns the next token of the data. It returns io.EOF, when the whole JSON value was read.
+func (t *Tokenizer) Next() (token Token, err error) {
+	if len(t.queue) != 0 {
+		return t.pop(), nil
+	}
+	if t.err != nil {
+		return token, t.err
+	}
+	for ; t.index < len(t.data); t.index++ {
+		if err = t.feed(t.data[t.index]); err != nil {
+			t.err = err
+			return token, err
+		}
+		if len(t.queue) != 0 {
+			t.index++
+			return t.pop(), nil
+		}
+	}
+	if !t.scanner.complete() {
+		t.err = errorIn(t.data, UnexpectedEOF, t.index)
+		return token, t.err
+	}
+	t.err = io.EOF
+	if isNumberState(t.scanner.state) {
+		t.scanner.state = OK
+		return t.token(TokenNumber, t.start, t.index), nil
+	}
+	return token, t.err
+}
+
+// feed moves the scanner with the current symbol and queues all tokens, that were finished by the symbol.
+func (t *Tokenizer) feed(c byte) error {
+	last := t.scanner.state
+	key := t.scanner.key
+	depth := len(t.scanner.stack)
+	if !t.scanner.feed(c) {
+		return errorIn(t.data, WrongSymbol, t.index)
+	}
+	state := t.scanner.state
+
+	if isNumberState(last) && !isNumberState(state) {
+		// number can be finished by the end of container, so depth is taken before the symbol
+		token := t.token(TokenNumber, t.start, t.index)
+		token.Depth = depth
+		t.queue = append(t.queue, token)
+	}
+	switch {
+	case last == ST && c == quotes:
+		if key {
+			t.queue = append(t.queue, t.token(TokenKey, t.start, t.index+1))
+		} else {
+			t.queue = append(t.queue, t.token(TokenString, t.start, t.index+1))
+		}
+	case (last == T3 || last == F4) && state == OK:
+		t.queue = append(t.queue, t.token(TokenBool, t.start, t.index+1))
+	case last == N3 && state == OK:
+		t.queue = append(t.queue, t.token(TokenNull, t.start, t.index+1))
+	case !isValueState(last) && isValueState(state):
+		t.start = t.index
+	case len(t.scanner.stack) > depth:
+		if t.scanner.top() == Object {
+			t.queue = append(t.queue, t.token(TokenObjectStart, t.index, t.index+1))
+		} else {
+			t.queue = append(t.queue, t.token(TokenArrayStart, t.index, t.index+1))
+		}
+	case len(t.scanner.stack) < depth:
+		if c == bracesR {
+			t.queue = append(t.queue, t.token(TokenObjectEnd, t.index, t.index+1))
+		} else {
+			t.queue = append(t.queue, t.token(TokenArrayEnd, t.index, t.index+1))
+		}
+	}
+	return nil
+}
+
+func (t *Tokenizer) token(_type TokenType, start, end int) Token {
+	depth := len(t.scanner.stack)
+	if _type == TokenObjectStart || _type == TokenArrayStart {
+		depth--
+	}
+	return Token{
+		Type:  _type,
+		Start: start,
+		End:   end,
+		Depth: depth,
+		Raw:   t.data[start:end],
+	}
+}
+
+func (t *Tokenizer) pop() (token Token) {
+	token = t.queue[0]
+	t.queue = t.queue[:copy(t.queue, t.queue[1:])]
+	return token
+}
+
+// Value calculates and returns a value of the scalar token.
+//
+// It returns string for TokenKey and TokenString, float64 for TokenNumber, bool for TokenBool and nil for others.
+func (t Token) Value() (value interface{}, err error) {
+	switch t.Type {
+	case TokenKey, TokenString:
+		value, ok := unquote(t.Raw, quotes)
+		if !ok {
+			return nil, errorAt(t.Start, quotes)
+		}
+		return value, nil
+	case TokenNumber:
+		return strconv.ParseFloat(string(t.Raw), 64)
+	case TokenBool:
+		return len(t.Raw) != 0 && t.Raw[0] == 't', nil
+	}
+	return nil, nil
+}
+
+// String is implementation of Stringer interface.
+func (t TokenType) String() string {
+	switch t {
+	case TokenObjectStart:
+		return "ObjectStart"
+	case TokenObjectEnd:
+		return "ObjectEnd"
+	case TokenArrayStart:
+		return "ArrayStart"
+	case TokenArrayEnd:
+		return "ArrayEnd"
+	case TokenKey:
+		return "Key"
+	case TokenString:
+		return "String"
+	case TokenNumber:
+		return "Number"
+	case TokenBool:
+		return "Bool"
+	case TokenNull:
+		return "Null"
+	}
+	return "Unknown"
+}
+
+func isNumberState(state States) bool {
+	return state >= MI && state <= E3
+}
+
+// isValueState returns true if state is inside the scalar value
+func isValueState(state States) bool {
+	return state >= ST && state <= N3
+}
@@ -0,0 +1,163 @@
+package ajson
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func ExampleTokenizer() {
+	tokenizer := NewTokenizer([]byte(`{"name": "value", "list": [1, true, null]}`))
+	for {
+		token, err := tokenizer.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%d %s %s\n", token.Depth, token.Type, token.Raw)
+	}
+	// Output:
+	// 0 ObjectStart {
+	// 1 Key "name"
+	// 1 String "value"
+	// 1 Key "list"
+	// 1 ArrayStart [
+	// 2 Number 1
+	// 2 Bool true
+	// 2 Null null
+	// 1 ArrayEnd ]
+	// 0 ObjectEnd }
+}
+
+func collectTokens(t *testing.T, data string) (result []string) {
+	tokenizer := NewTokenizer([]byte(data))
+	result = make([]string, 0)
+	for {
+		token, err := tokenizer.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %s", err)
+		}
+		if string(token.Raw) != data[token.Start:token.End] {
+			t.Errorf("Next() wrong borders: %s != %s", token.Raw, data[token.Start:token.End])
+		}
+		result = append(result, fmt.Sprintf("%s:%s", token.Type, token.Raw))
+	}
+}
+
+func TestTokenizer_Next(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "number", input: "123", expected: []string{"Number:123"}},
+		{name: "number spaced", input: " -1.5e+3 ", expected: []string{"Number:-1.5e+3"}},
+		{name: "string", input: `"foo\"bar"`, expected: []string{`String:"foo\"bar"`}},
+		{name: "literals", input: `[true,false,null]`, expected: []string{"ArrayStart:[", "Bool:true", "Bool:false", "Null:null", "ArrayEnd:]"}},
+		{name: "empty object", input: `{}`, expected: []string{"ObjectStart:{", "ObjectEnd:}"}},
+		{name: "empty array", input: `[ ]`, expected: []string{"ArrayStart:[", "ArrayEnd:]"}},
+		{
+			name:     "number before close",
+			input:    `{"a":1,"b":[2]}`,
+			expected: []string{"ObjectStart:{", `Key:"a"`, "Number:1", `Key:"b"`, "ArrayStart:[", "Number:2", "ArrayEnd:]", "ObjectEnd:}"},
+		},
+		{
+			name:     "nested",
+			input:    `[{"a":{"b":[]}}, "c"]`,
+			expected: []string{"ArrayStart:[", "ObjectStart:{", `Key:"a"`, "ObjectStart:{", `Key:"b"`, "ArrayStart:[", "ArrayEnd:]", "ObjectEnd:}", "ObjectEnd:}", `String:"c"`, "ArrayEnd:]"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := collectTokens(t, test.input); !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Next() wrong tokens:\n%v\n%v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestTokenizer_Next_depth(t *testing.T) {
+	tokenizer := NewTokenizer([]byte(`{"a":[1,[2]],"b":3}`))
+	expected := []int{0, 1, 1, 2, 2, 3, 2, 1, 1, 1, 0}
+	for i, depth := range expected {
+		token, err := tokenizer.Next()
+		if err != nil {
+			t.Fatalf("Next() unexpected error: %s", err)
+		}
+		if token.Depth != depth {
+			t.Errorf("Next() wrong depth of %d token %s: %d", i, token.Raw, token.Depth)
+		}
+	}
+}
+
+func TestTokenizer_Next_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		_type ErrorType
+		index int
+	}{
+		{name: "empty", input: "", _type: UnexpectedEOF, index: 0},
+		{name: "blank", input: "  ", _type: UnexpectedEOF, index: 2},
+		{name: "unclosed", input: `{"a":[1`, _type: UnexpectedEOF, index: 7},
+		{name: "second value", input: `{} {}`, _type: WrongSymbol, index: 3},
+		{name: "wrong close", input: `{"a":1]`, _type: WrongSymbol, index: 6},
+		{name: "wrong literal", input: `[tru]`, _type: WrongSymbol, index: 4},
+		{name: "key without value", input: `{"a"}`, _type: WrongSymbol, index: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokenizer := NewTokenizer([]byte(test.input))
+			var err error
+			for err == nil {
+				_, err = tokenizer.Next()
+			}
+			var jerr Error
+			if !errors.As(err, &jerr) {
+				t.Fatalf("Next() expected Error, got: %v", err)
+			}
+			if jerr.Type != test._type || jerr.Index != test.index {
+				t.Errorf("Next() wrong error: %#v", jerr)
+			}
+			if _, err2 := tokenizer.Next(); err2 != err {
+				t.Errorf("Next() error should be persistent: %v", err2)
+			}
+		})
+	}
+}
+
+func TestToken_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    Token
+		expected interface{}
+		err      bool
+	}{
+		{name: "key", token: Token{Type: TokenKey, Raw: []byte(`"k\ney"`)}, expected: "k\ney"},
+		{name: "string", token: Token{Type: TokenString, Raw: []byte(`"A"`)}, expected: "A"},
+		{name: "wrong string", token: Token{Type: TokenString, Raw: []byte(`"\x"`)}, err: true},
+		{name: "number", token: Token{Type: TokenNumber, Raw: []byte(`-1.5e1`)}, expected: -15.0},
+		{name: "true", token: Token{Type: TokenBool, Raw: []byte(`true`)}, expected: true},
+		{name: "false", token: Token{Type: TokenBool, Raw: []byte(`false`)}, expected: false},
+		{name: "null", token: Token{Type: TokenNull, Raw: []byte(`null`)}, expected: nil},
+		{name: "container", token: Token{Type: TokenArrayStart, Raw: []byte(`[`)}, expected: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := test.token.Value()
+			if (err != nil) != test.err {
+				t.Fatalf("Value() unexpected error: %v", err)
+			}
+			if !test.err && value != test.expected {
+				t.Errorf("Value() wrong result: %#v != %#v", value, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package ajson
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// UnpackOptions are the options of Node.UnpackWithOptions
+type UnpackOptions struct {
+	// UseNumber unpacks numbers into json.Number exactly as they were written in the source, instead of float64.
+	UseNumber bool
+	// UseInt64WhenIntegral unpacks integral numbers (e.g. `1`, `1.0` or `1e3`), that fit into int64, into int64.
+	// It takes precedence over UseNumber.
+	UseInt64WhenIntegral bool
+	// OrderedObjects unpacks objects into OrderedObject, instead of map[string]interface{}, to keep the order of keys.
+	OrderedObjects bool
+}
+
+// OrderedObject is the unpacked object, which keeps the order of keys. It is marshaled by json.Marshal in that order.
+type OrderedObject []ObjectEntry
+
+// ObjectEntry is the key-value pair of OrderedObject
+type ObjectEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// UnpackWithOptions will produce current node to it's interface, recursively with all underlying nodes, in the same
+// way as Node.Unpack does, but numbers and objects are unpacked in accordance to the options.
+func (n *Node) UnpackWithOptions(options UnpackOptions) (value interface{}, err error) {
+	if n == nil {
+		return nil, errorUnparsed()
+	}
+	switch n._type {
+	case Null:
+		return nil, nil
+	case Numeric:
+		return n.unpackNumber(options)
+	case String:
+		value, err = n.Value()
+		if _, ok := value.(string); !ok {
+			return nil, errorType()
+		}
+	case Bool:
+		value, err = n.Value()
+		if _, ok := value.(bool); !ok {
+			return nil, errorType()
+		}
+	case Array:
+		n.load()
+		children := make([]interface{}, len(n.children))
+		for _, child := range n.children {
+			val, err := child.UnpackWithOptions(options)
+			if err != nil {
+				return nil, err
+			}
+			children[*child.index] = val
+		}
+		value = children
+	case Object:
+		n.load()
+		if options.OrderedObjects {
+			result := make(OrderedObject, 0, len(n.children))
+			for _, key := range n.Keys() {
+				val, err := n.children[key].UnpackWithOptions(options)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, ObjectEntry{Key: key, Value: val})
+			}
+			return result, nil
+		}
+		result := make(map[string]interface{}, len(n.children))
+		for key, child := range n.children {
+			result[key], err = child.UnpackWithOptions(options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		value = result
+	}
+	return
+}
+
+// unpackNumber returns value of the Numeric node as float64, int64 or json.Number
+func (n *Node) unpackNumber(options UnpackOptions) (interface{}, error) {
+	value, err := n.GetNumeric()
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		// JSON5 NaN and Infinity have no other representation
+		return value, nil
+	}
+	if options.UseInt64WhenIntegral && value == math.Trunc(value) && value >= math.MinInt64 && value <= math.MaxInt64 {
+		if integer, err := n.GetInt64(); err == nil {
+			return integer, nil
+		}
+	}
+	if options.UseNumber {
+		number, err := n.GetNumberString()
+		if err != nil {
+			return nil, err
+		}
+		if !isNumber([]byte(number)) {
+			// JSON5 hexadecimal numbers, or numbers with the leading plus sign
+			number = string(formatFloat(value))
+		}
+		return json.Number(number), nil
+	}
+	return value, nil
+}
+
+// Get returns the value by the key
+func (o OrderedObject) Get(key string) (value interface{}, ok bool) {
+	for _, entry := range o {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON returns the object with keys in the order of the entries
+func (o OrderedObject) MarshalJSON() ([]byte, error) {
+	result := []byte{bracesL}
+	for i, entry := range o {
+		if i != 0 {
+			result = append(result, coma)
+		}
+		value, err := json.Marshal(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, quotes)
+		result = append(result, quoteString(entry.Key, true, false, false)...)
+		result = append(result, quotes, colon)
+		result = append(result, value...)
+	}
+	return append(result, bracesR), nil
+}
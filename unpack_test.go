@@ -0,0 +1,113 @@
+package ajson
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleNode_UnpackWithOptions() {
+	root := Must(Unmarshal([]byte(`{"id": 9007199254740993, "price": 8.95, "name": "book", "tags": {"z": 1, "a": 2.0}}`)))
+	value, err := root.UnpackWithOptions(UnpackOptions{UseNumber: true, UseInt64WhenIntegral: true, OrderedObjects: true})
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range value.(OrderedObject) {
+		fmt.Printf("%s: %T\n", entry.Key, entry.Value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	// Output:
+	// id: int64
+	// price: json.Number
+	// name: string
+	// tags: ajson.OrderedObject
+	// {"id":9007199254740993,"price":8.95,"name":"book","tags":{"z":1,"a":2}}
+}
+
+func TestNode_UnpackWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		options  UnpackOptions
+		expected interface{}
+	}{
+		{name: "default", input: `[1, 1.5, 1e2, 12345678901234567890]`, expected: []interface{}{1.0, 1.5, 100.0, 12345678901234567890.0}},
+		{name: "UseNumber", input: `[1, 1.50, 1e2, 12345678901234567890]`, options: UnpackOptions{UseNumber: true},
+			expected: []interface{}{json.Number("1"), json.Number("1.50"), json.Number("1e2"), json.Number("12345678901234567890")}},
+		{name: "UseInt64WhenIntegral", input: `[1, -1.0, 1.5, 1e2, 9007199254740993, 9223372036854775808, 1e300]`, options: UnpackOptions{UseInt64WhenIntegral: true},
+			expected: []interface{}{int64(1), int64(-1), 1.5, int64(100), int64(9007199254740993), 9223372036854775808.0, 1e300}},
+		{name: "both", input: `[1, 1.5, 9223372036854775808]`, options: UnpackOptions{UseNumber: true, UseInt64WhenIntegral: true},
+			expected: []interface{}{int64(1), json.Number("1.5"), json.Number("9223372036854775808")}},
+		{name: "map", input: `{"b": {"c": null}, "a": [true, "x"]}`,
+			expected: map[string]interface{}{"b": map[string]interface{}{"c": nil}, "a": []interface{}{true, "x"}}},
+		{name: "OrderedObjects", input: `{"b": {"c": null}, "a": [true, {}]}`, options: UnpackOptions{OrderedObjects: true},
+			expected: OrderedObject{{Key: "b", Value: OrderedObject{{Key: "c", Value: nil}}}, {Key: "a", Value: []interface{}{true, OrderedObject{}}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				root := Must(UnmarshalWithOptions([]byte(test.input), Options{Lazy: lazy}))
+				value, err := root.UnpackWithOptions(test.options)
+				if err != nil {
+					t.Fatalf("UnpackWithOptions() unexpected error: %s", err)
+				}
+				if !reflect.DeepEqual(value, test.expected) {
+					t.Errorf("UnpackWithOptions() wrong result: %#v", value)
+				}
+			}
+		})
+	}
+}
+
+func TestNode_UnpackWithOptions_nodes(t *testing.T) {
+	root := ObjectNode("", map[string]*Node{"b": NumericNode("", 0.1), "a": NumericNode("", 3)})
+	if err := root.AppendObject("c", NumericNode("", 1e21)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	value, err := root.UnpackWithOptions(UnpackOptions{UseNumber: true, OrderedObjects: true})
+	if err != nil {
+		t.Fatalf("UnpackWithOptions() unexpected error: %s", err)
+	}
+	expected := OrderedObject{{Key: "a", Value: json.Number("3")}, {Key: "b", Value: json.Number("0.1")}, {Key: "c", Value: json.Number("1e+21")}}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("UnpackWithOptions() wrong result: %#v", value)
+	}
+
+	root = Must(UnmarshalJSON5([]byte(`[0x10, +1, Infinity]`)))
+	value, err = root.UnpackWithOptions(UnpackOptions{UseNumber: true})
+	if err != nil {
+		t.Fatalf("UnpackWithOptions() unexpected error: %s", err)
+	}
+	if result := fmt.Sprint(value); result != "[16 1 +Inf]" {
+		t.Errorf("UnpackWithOptions() wrong result: %#v", value)
+	}
+
+	if _, err = (*Node)(nil).UnpackWithOptions(UnpackOptions{}); err == nil {
+		t.Errorf("UnpackWithOptions() expected error")
+	}
+}
+
+func TestOrderedObject(t *testing.T) {
+	object := OrderedObject{{Key: "b", Value: 1}, {Key: "<a>", Value: OrderedObject{{Key: "z", Value: nil}, {Key: "y", Value: "x"}}}}
+	data, err := json.Marshal(object)
+	if err != nil {
+		t.Fatalf("MarshalJSON() unexpected error: %s", err)
+	}
+	if string(data) != `{"b":1,"\u003ca\u003e":{"z":null,"y":"x"}}` {
+		t.Errorf("MarshalJSON() wrong result: %s", data)
+	}
+	if value, ok := object.Get("b"); !ok || value != 1 {
+		t.Errorf("Get() wrong result: %v", value)
+	}
+	if _, ok := object.Get("c"); ok {
+		t.Errorf("Get() unexpected key")
+	}
+	if _, err = json.Marshal(OrderedObject{{Key: "a", Value: make(chan int)}}); err == nil {
+		t.Errorf("MarshalJSON() expected error")
+	}
+}
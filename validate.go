@@ -0,0 +1,97 @@
+package ajson
+
+import (
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	. "github.com/spyzhov/ajson/internal"
+)
+
+// scanners is a pool of scanners for the validation, to avoid allocations of their stacks
+var scanners = sync.Pool{
+	New: func() interface{} {
+		return &scanner{stack: make([]NodeType, 0, 32)}
+	},
+}
+
+// Valid reports whether data is a valid JSON value. It walks through the data with the same state machine as
+// Unmarshal, but doesn't create any Node.
+func Valid(data []byte) bool {
+	s := scanners.Get().(*scanner)
+	defer scanners.Put(s)
+	s.reset()
+	for _, c := range data {
+		if !s.feed(c) {
+			return false
+		}
+	}
+	return s.complete()
+}
+
+// ValidateStrict checks the data to be a valid JSON value, in accordance to RFC 8259.
+//
+// In addition to the checks of Unmarshal, it rejects invalid UTF-8 sequences and lone surrogates in the
+// escaped strings (e.g. "\ud800"). Returned error has the position of the first wrong symbol.
+func ValidateStrict(data []byte) error {
+	s := scanners.Get().(*scanner)
+	defer scanners.Put(s)
+	s.reset()
+	start := 0
+	for i, c := range data {
+		last := s.state
+		if !s.feed(c) {
+			return errorIn(data, WrongSymbol, i)
+		}
+		if !isStringState(last) && isStringState(s.state) {
+			start = i
+		} else if isStringState(last) && !isStringState(s.state) {
+			if index := invalidString(data[start+1 : i]); index != -1 {
+				return errorIn(data, WrongSymbol, start+1+index)
+			}
+		}
+	}
+	if !s.complete() {
+		return errorIn(data, UnexpectedEOF, len(data))
+	}
+	return nil
+}
+
+// invalidString returns index of the first invalid UTF-8 sequence or lone surrogate escape in the content of
+// the string, which was already checked by the state machine, or -1 if there is no one.
+func invalidString(s []byte) int {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return i
+			}
+			i += size
+			continue
+		}
+		if c != backslash {
+			i++
+			continue
+		}
+		if s[i+1] != 'u' {
+			i += 2
+			continue
+		}
+		r := getu4(s[i:])
+		if !utf16.IsSurrogate(r) {
+			i += 6
+			continue
+		}
+		if r2 := getu4(s[i+6:]); r >= 0xDC00 || r2 < 0xDC00 || r2 > 0xDFFF {
+			return i
+		}
+		i += 12
+	}
+	return -1
+}
+
+// isStringState returns true if state is inside the string value or key
+func isStringState(state States) bool {
+	return state >= ST && state <= U4
+}
@@ -0,0 +1,111 @@
+package ajson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleValid() {
+	fmt.Println(Valid([]byte(`{"foo":[1,true,null]}`)))
+	fmt.Println(Valid([]byte(`{"foo":[1,true,null]`)))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleValidateStrict() {
+	err := ValidateStrict([]byte(`{"foo":"\ud800"}`))
+	fmt.Println(err)
+	// Output:
+	// wrong symbol '\' at 8 (line 1, column 9)
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "number", input: `123`, expected: true},
+		{name: "spaced number", input: " \r\n\t-1.5e+3 \n", expected: true},
+		{name: "string", input: `"foo\"barA"`, expected: true},
+		{name: "object", input: `{"foo":{"bar":[1,"2",{},[]]},"baz":null}`, expected: true},
+		{name: "literals", input: `[true,false,null]`, expected: true},
+		{name: "deep", input: strings.Repeat("[", 100) + strings.Repeat("]", 100), expected: true},
+		{name: "empty", input: ``, expected: false},
+		{name: "blank", input: `  `, expected: false},
+		{name: "unclosed", input: `{"foo":[1`, expected: false},
+		{name: "second value", input: `{} {}`, expected: false},
+		{name: "wrong close", input: `[1}`, expected: false},
+		{name: "trailing comma", input: `[1,]`, expected: false},
+		{name: "leading zero", input: `01`, expected: false},
+		{name: "control character", input: "\"a\tb\"", expected: false},
+		{name: "wrong escape", input: `"\'"`, expected: false},
+		{name: "wrong literal", input: `[nul]`, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := Valid([]byte(test.input)); result != test.expected {
+				t.Errorf("Valid() wrong result: %v", result)
+			}
+			_, err := Unmarshal([]byte(test.input))
+			if (err == nil) != test.expected {
+				t.Errorf("Valid() and Unmarshal() mismatch: %v", err)
+			}
+		})
+	}
+}
+
+func TestValid_allocs(t *testing.T) {
+	data := []byte(`{"foo":{"bar":[1,"2",{},[]]},"baz":null}`)
+	if allocs := testing.AllocsPerRun(100, func() { Valid(data) }); allocs != 0 {
+		t.Errorf("Valid() allocations: %v", allocs)
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		_type ErrorType
+		index int
+		valid bool
+	}{
+		{name: "valid", input: `{"foo":["bar",1,null]}`, valid: true},
+		{name: "unicode", input: `"привет 😀"`, valid: true},
+		{name: "surrogate pair", input: `["\ud83d\ude00"]`, valid: true},
+		{name: "escaped key", input: `{"A\"":1}`, valid: true},
+		{name: "lone high surrogate", input: `["\ud83d"]`, _type: WrongSymbol, index: 2},
+		{name: "lone low surrogate", input: `["a\ude00"]`, _type: WrongSymbol, index: 3},
+		{name: "reversed pair", input: `"\ude00\ud83d"`, _type: WrongSymbol, index: 1},
+		{name: "high surrogate with escape", input: `"\ud83d\n"`, _type: WrongSymbol, index: 1},
+		{name: "surrogate in key", input: `{"\udfff":1}`, _type: WrongSymbol, index: 2},
+		{name: "invalid utf-8", input: "[\"a\xffb\"]", _type: WrongSymbol, index: 3},
+		{name: "truncated utf-8", input: "\"\xd0\"", _type: WrongSymbol, index: 1},
+		{name: "utf-8 outside of string", input: "[\xd0\xb0]", _type: WrongSymbol, index: 1},
+		{name: "control character", input: "\"\x01\"", _type: WrongSymbol, index: 1},
+		{name: "wrong symbol", input: `[1,]`, _type: WrongSymbol, index: 3},
+		{name: "unexpected end", input: `{"foo":`, _type: UnexpectedEOF, index: 7},
+		{name: "empty", input: ``, _type: UnexpectedEOF, index: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateStrict([]byte(test.input))
+			if test.valid {
+				if err != nil {
+					t.Errorf("ValidateStrict() unexpected error: %s", err)
+				}
+				return
+			}
+			var jerr Error
+			if !errors.As(err, &jerr) {
+				t.Fatalf("ValidateStrict() expected Error, got: %v", err)
+			}
+			if jerr.Type != test._type || jerr.Index != test.index {
+				t.Errorf("ValidateStrict() wrong error: %s", jerr)
+			}
+		})
+	}
+}
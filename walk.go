@@ -0,0 +1,94 @@
+package ajson
+
+import (
+	"sort"
+	"sync"
+)
+
+// WalkAction is the result of the visitor function, that controls the walk over the tree
+type WalkAction int
+
+const (
+	// WalkContinue continues the walk
+	WalkContinue WalkAction = iota
+	// WalkSkip skips the children of the current node, it's the same as WalkContinue for the post-order walk
+	WalkSkip
+	// WalkStop stops the walk
+	WalkStop
+)
+
+// sortedKeys is the reusable buffer for the sorted keys of an object
+type sortedKeys struct {
+	keys []string
+}
+
+var sortedKeysPool = sync.Pool{
+	New: func() interface{} {
+		return new(sortedKeys)
+	},
+}
+
+func (k *sortedKeys) Len() int           { return len(k.keys) }
+func (k *sortedKeys) Less(i, j int) bool { return k.keys[i] < k.keys[j] }
+func (k *sortedKeys) Swap(i, j int)      { k.keys[i], k.keys[j] = k.keys[j], k.keys[i] }
+
+// Walk visits the node and all its descendants in pre-order: the node is visited before its children. Children are
+// visited in the same order as Inheritors returns them. Visitor receives the depth of the node, starting from 0 for
+// the current one, and returns the action: WalkContinue, WalkSkip to skip the children of the node, or WalkStop.
+//
+// The tree should not be changed during the walk.
+func (n *Node) Walk(visitor func(node *Node, depth int) WalkAction) {
+	if n != nil {
+		n.walk(0, true, visitor)
+	}
+}
+
+// WalkPostOrder visits the node and all its descendants in post-order: the node is visited after its children. See
+// Walk for details.
+func (n *Node) WalkPostOrder(visitor func(node *Node, depth int) WalkAction) {
+	if n != nil {
+		n.walk(0, false, visitor)
+	}
+}
+
+// walk visits the node and its descendants, and returns false if the walk was stopped
+func (n *Node) walk(depth int, pre bool, visitor func(node *Node, depth int) WalkAction) bool {
+	if pre {
+		switch visitor(n, depth) {
+		case WalkStop:
+			return false
+		case WalkSkip:
+			return true
+		}
+	}
+	if !n.eachInheritor(func(child *Node) bool {
+		return child.walk(depth+1, pre, visitor)
+	}) {
+		return false
+	}
+	return pre || visitor(n, depth) != WalkStop
+}
+
+// eachInheritor calls the callback for each child of the container in the order of Inheritors, without allocations,
+// until it returns false. Returns false if the iteration was stopped.
+func (n *Node) eachInheritor(callback func(child *Node) bool) bool {
+	n.load()
+	if !n.IsObject() {
+		return n.eachChild(func(_ string, _ int, child *Node) bool {
+			return callback(child)
+		})
+	}
+	keys := sortedKeysPool.Get().(*sortedKeys)
+	keys.keys = append(keys.keys[:0], n.keys...)
+	sort.Sort(keys)
+	defer func() {
+		keys.keys = keys.keys[:0]
+		sortedKeysPool.Put(keys)
+	}()
+	for _, key := range keys.keys {
+		if child, ok := n.children[key]; ok && !callback(child) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package ajson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ExampleNode_Walk() {
+	root := Must(Unmarshal([]byte(`{"store": {"book": [{"title": "Moby Dick", "price": 8.99}], "bicycle": {"color": "red"}}}`)))
+	root.Walk(func(node *Node, depth int) WalkAction {
+		if node.Key() == "book" {
+			return WalkSkip
+		}
+		fmt.Println(strings.Repeat("  ", depth) + node.Path())
+		return WalkContinue
+	})
+	// Output:
+	// $
+	//   $['store']
+	//     $['store']['bicycle']
+	//       $['store']['bicycle']['color']
+}
+
+func TestNode_Walk(t *testing.T) {
+	data := []byte(`{"b": [1, {"d": 2, "c": 3}], "a": {"e": []}, "f": null}`)
+	visited := func(root *Node, walk func(*Node, func(*Node, int) WalkAction), action func(node *Node) WalkAction) (result []string) {
+		walk(root, func(node *Node, depth int) WalkAction {
+			result = append(result, fmt.Sprintf("%d:%s", depth, node.Pointer()))
+			return action(node)
+		})
+		return
+	}
+	preOrder := (*Node).Walk
+	postOrder := (*Node).WalkPostOrder
+	tests := []struct {
+		name     string
+		walk     func(*Node, func(*Node, int) WalkAction)
+		action   func(node *Node) WalkAction
+		expected []string
+	}{
+		{
+			name:     "pre-order",
+			walk:     preOrder,
+			action:   func(*Node) WalkAction { return WalkContinue },
+			expected: []string{"0:", "1:/a", "2:/a/e", "1:/b", "2:/b/0", "2:/b/1", "3:/b/1/c", "3:/b/1/d", "1:/f"},
+		},
+		{
+			name:     "post-order",
+			walk:     postOrder,
+			action:   func(*Node) WalkAction { return WalkContinue },
+			expected: []string{"2:/a/e", "1:/a", "2:/b/0", "3:/b/1/c", "3:/b/1/d", "2:/b/1", "1:/b", "1:/f", "0:"},
+		},
+		{
+			name: "pre-order skip",
+			walk: preOrder,
+			action: func(node *Node) WalkAction {
+				if node.IsArray() {
+					return WalkSkip
+				}
+				return WalkContinue
+			},
+			expected: []string{"0:", "1:/a", "2:/a/e", "1:/b", "1:/f"},
+		},
+		{
+			name: "post-order skip",
+			walk: postOrder,
+			action: func(node *Node) WalkAction {
+				return WalkSkip
+			},
+			expected: []string{"2:/a/e", "1:/a", "2:/b/0", "3:/b/1/c", "3:/b/1/d", "2:/b/1", "1:/b", "1:/f", "0:"},
+		},
+		{
+			name: "pre-order stop",
+			walk: preOrder,
+			action: func(node *Node) WalkAction {
+				if node.Key() == "c" {
+					return WalkStop
+				}
+				return WalkContinue
+			},
+			expected: []string{"0:", "1:/a", "2:/a/e", "1:/b", "2:/b/0", "2:/b/1", "3:/b/1/c"},
+		},
+		{
+			name: "post-order stop",
+			walk: postOrder,
+			action: func(node *Node) WalkAction {
+				if node.Key() == "a" {
+					return WalkStop
+				}
+				return WalkContinue
+			},
+			expected: []string{"2:/a/e", "1:/a"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				root := Must(UnmarshalWithOptions(data, Options{Lazy: lazy}))
+				result := visited(root, test.walk, test.action)
+				if !reflect.DeepEqual(result, test.expected) {
+					t.Errorf("Walk() wrong result: %v", result)
+				}
+			}
+		})
+	}
+	(*Node)(nil).Walk(func(*Node, int) WalkAction {
+		t.Errorf("Walk() visited nil node")
+		return WalkContinue
+	})
+}
+
+func TestNode_Walk_order(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"z": [3, 2, 1, 0, 9, 8, 7, 6, 5, 4, 10, 11], "y": {"c": 1, "a": 2, "b": 3}, "x": 0}`)))
+	var result []*Node
+	root.Walk(func(node *Node, _ int) WalkAction {
+		result = append(result, node)
+		return WalkContinue
+	})
+	expected := append([]*Node{root}, recursiveInheritors(root)...)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Walk() wrong order: %v", result)
+	}
+}
+
+// recursiveInheritors returns all the descendants of the node in pre-order, based on Inheritors
+func recursiveInheritors(node *Node) (result []*Node) {
+	for _, child := range node.Inheritors() {
+		result = append(result, child)
+		result = append(result, recursiveInheritors(child)...)
+	}
+	return
+}
+
+// raceEnabled is true, if the tests are running with the race detector
+var raceEnabled bool
+
+func TestNode_Walk_allocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not checked with the race detector")
+	}
+	root := Must(Unmarshal([]byte(`{"b": [1, {"d": 2, "c": 3}], "a": {"e": [[], {}, "f"]}}`)))
+	count := 0
+	visitor := func(*Node, int) WalkAction {
+		count++
+		return WalkContinue
+	}
+	root.Walk(visitor)
+	if allocations := testing.AllocsPerRun(100, func() { root.Walk(visitor) }); allocations > 0 {
+		t.Errorf("Walk() wrong allocations: %v", allocations)
+	}
+	if allocations := testing.AllocsPerRun(100, func() { root.WalkPostOrder(visitor) }); allocations > 0 {
+		t.Errorf("WalkPostOrder() wrong allocations: %v", allocations)
+	}
+}